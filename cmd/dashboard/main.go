@@ -2,78 +2,327 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	// Embedded so SYNCTHING_DASHBOARD_TIMEZONE validates on images without tzdata.
+	_ "time/tzdata"
+
 	"syncthing-dashboard/internal/collector"
 	"syncthing-dashboard/internal/config"
 	"syncthing-dashboard/internal/demo"
+	"syncthing-dashboard/internal/history"
 	httpapi "syncthing-dashboard/internal/http"
 	"syncthing-dashboard/internal/model"
 	"syncthing-dashboard/internal/syncthing"
+	"syncthing-dashboard/internal/version"
 )
 
 type dashboardService interface {
 	Start(context.Context)
 	Snapshot() (model.DashboardSnapshot, bool)
+	Updated() <-chan struct{}
 	Ready() bool
+	History(limit int) []history.Entry
+	RefreshNow(ctx context.Context) error
 }
 
 func main() {
-	if err := run(); err != nil {
+	checkOnly := flag.Bool("check", false, "validate configuration and Syncthing connectivity, then exit")
+	flag.Parse()
+
+	if err := run(*checkOnly); err != nil {
 		slog.Error("dashboard failed", "error", err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
+func run(checkOnly bool) error {
 	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
 
+	if checkOnly || cfg.CheckOnly {
+		return runCheck(context.Background(), cfg, os.Stdout)
+	}
+
+	model.SetByteCountsAsStrings(cfg.BigIntAsString)
+
+	diskSpace := model.DiskSpaceThresholds{
+		MinFreeBytes: cfg.LowDiskFreeBytes,
+		MinFreePct:   cfg.LowDiskFreePct,
+	}
+
 	var dashboardSvc dashboardService
 	if cfg.DemoMode {
-		slog.Info("SYNCTHING_BASE_URL is not set; running in demonstration mode")
-		dashboardSvc = demo.NewCollector(cfg.PollInterval)
+		slog.Warn("SYNCTHING_BASE_URL is not set; running in demonstration mode with synthetic data",
+			"hint", "set SYNCTHING_DASHBOARD_ALLOW_DEMO=false to fail instead")
+		dashboardSvc = demo.NewCollector(demo.Options{
+			PollInterval:       cfg.PollInterval,
+			StaleAfter:         cfg.StaleAfter,
+			DiskSpace:          diskSpace,
+			HistorySize:        cfg.HistorySize,
+			FolderSort:         cfg.FolderSort,
+			RemoteSort:         cfg.RemoteSort,
+			MinVersion:         cfg.MinVersion,
+			FolderFilter:       cfg.FolderFilter,
+			DeviceFilter:       cfg.DeviceFilter,
+			FolderGroups:       cfg.FolderGroups,
+			Scenario:           cfg.DemoScenario,
+			Folders:            cfg.DemoFolders,
+			Remotes:            cfg.DemoRemotes,
+			SuppressSeverities: cfg.SuppressSeverities,
+			MaxAlerts:          cfg.MaxAlerts,
+		})
 	} else {
-		client := syncthing.NewClient(cfg.STBaseURL, cfg.STAPIKey, cfg.STTimeout, cfg.STInsecureSkipVerify)
-		dashboardSvc = collector.New(client, cfg.PollInterval)
+		client := syncthing.NewClient(cfg.STBaseURL, cfg.STAPIKey, cfg.STTimeout, cfg.STInsecureSkipVerify, clientOptions(cfg))
+		dashboardSvc = collector.New(client, collector.Options{
+			PollInterval: cfg.PollInterval,
+			PollTimeout:  cfg.PollTimeout,
+			PollJitter:   cfg.PollJitter,
+			StaleAfter:   cfg.StaleAfter,
+
+			CollectRemoteCompletion: cfg.CollectRemoteCompletion,
+			CollectContributors:     cfg.CollectContributors,
+			CollectPending:          cfg.CollectPending,
+			CollectScanProgress:     cfg.CollectScanProgress,
+			CollectIgnores:          cfg.CollectIgnores,
+			FlapThreshold:           cfg.FlapThreshold,
+			FlapWindow:              cfg.FlapWindow,
+			DiskSpace:               diskSpace,
+			HistorySize:             cfg.HistorySize,
+			FolderSort:              cfg.FolderSort,
+			RemoteSort:              cfg.RemoteSort,
+			MinVersion:              cfg.MinVersion,
+			FolderFilter:            cfg.FolderFilter,
+			DeviceFilter:            cfg.DeviceFilter,
+			FolderGroups:            cfg.FolderGroups,
+			SuppressSeverities:      cfg.SuppressSeverities,
+			MaxAlerts:               cfg.MaxAlerts,
+			ConnectivityWindow:      cfg.ConnectivityWindow,
+			MaxFolders:              cfg.MaxFolders,
+		})
 	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 	dashboardSvc.Start(ctx)
 
+	apiOpts := httpapi.Options{
+		PageTitle:       cfg.PageTitle,
+		PageSubtitle:    cfg.PageSubtitle,
+		InstanceName:    cfg.InstanceName,
+		PollInterval:    cfg.PollInterval,
+		LongPollTimeout: cfg.LongPollTimeout,
+		Timezone:        cfg.Timezone,
+		EffectiveConfig: cfg.Redacted(),
+		EnablePprof:     cfg.EnablePprof,
+		SeparateAdmin:   cfg.HTTPAdminListenAddr != "",
+		RateLimit:       cfg.RateLimit,
+		MaxConcurrent:   cfg.MaxConcurrentRequests,
+		AccessLogProbes: cfg.AccessLogProbes,
+		BasicUser:       cfg.BasicUser,
+		BasicPassword:   cfg.BasicPassword,
+	}
+	if cfg.AccessLog {
+		apiOpts.AccessLog = slog.Default()
+	}
+	api := httpapi.New(dashboardSvc, apiOpts)
 	server := &http.Server{
 		Addr:         cfg.HTTPListenAddr,
-		Handler:      httpapi.New(dashboardSvc, cfg.PageTitle, cfg.PageSubtitle, cfg.PollInterval),
+		Handler:      api,
 		ReadTimeout:  cfg.HTTPReadTimeout,
 		WriteTimeout: cfg.HTTPWriteTimeout,
 	}
+	server.RegisterOnShutdown(api.Close)
+	if cfg.HTTPTLSCert != nil {
+		server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{*cfg.HTTPTLSCert},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+
+	ln, cleanup, err := listen(cfg.HTTPListenAddr)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	slog.Info("read-only Syncthing dashboard listening", "addr", cfg.HTTPListenAddr, "tls", server.TLSConfig != nil, "version", version.Version)
+	if cfg.HTTPAdminListenAddr == "" {
+		return serve(ctx, server, ln, cfg.ShutdownTimeout)
+	}
+
+	adminServer := &http.Server{
+		Addr:         cfg.HTTPAdminListenAddr,
+		Handler:      api.AdminHandler(),
+		ReadTimeout:  cfg.HTTPReadTimeout,
+		WriteTimeout: cfg.HTTPWriteTimeout,
+	}
+	adminLn, adminCleanup, err := listen(cfg.HTTPAdminListenAddr)
+	if err != nil {
+		return err
+	}
+	defer adminCleanup()
+
+	slog.Info("admin endpoints listening", "addr", cfg.HTTPAdminListenAddr)
+	return servePair(ctx, server, ln, adminServer, adminLn, cfg.ShutdownTimeout)
+}
+
+// servePair runs two servers as serve does and shuts both down together, when
+// ctx is cancelled or as soon as either one stops on its own.
+func servePair(ctx context.Context, server *http.Server, ln net.Listener, other *http.Server, otherLn net.Listener, shutdownTimeout time.Duration) error {
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	otherErr := make(chan error, 1)
+	go func() {
+		defer stop()
+		otherErr <- serve(ctx, other, otherLn, shutdownTimeout)
+	}()
+
+	err := serve(ctx, server, ln, shutdownTimeout)
+	stop()
+	return errors.Join(err, <-otherErr)
+}
+
+// serve runs server on ln until ctx is cancelled, then gives in-flight
+// requests up to shutdownTimeout to finish before closing them. When
+// server.TLSConfig is set ln serves TLS, with HTTP/2 offered over ALPN.
+func serve(ctx context.Context, server *http.Server, ln net.Listener, shutdownTimeout time.Duration) error {
+	var inFlight atomic.Int64
+	handler := server.Handler
+	server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inFlight.Add(1)
+		defer inFlight.Add(-1)
+		handler.ServeHTTP(w, r)
+	})
 
 	shutdownDone := make(chan struct{})
 	go func() {
-		<-ctx.Done()
 		defer close(shutdownDone)
+		<-ctx.Done()
 
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		slog.Info("shutting down", "in_flight", inFlight.Load(), "timeout", shutdownTimeout)
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
 		defer shutdownCancel()
 		if err := server.Shutdown(shutdownCtx); err != nil {
-			slog.Error("shutdown error", "error", err)
+			slog.Error("shutdown error", "error", err, "in_flight", inFlight.Load())
+			_ = server.Close()
 		}
 	}()
 
-	slog.Info("read-only Syncthing dashboard listening", "addr", cfg.HTTPListenAddr)
-	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	serveFn := server.Serve
+	if server.TLSConfig != nil {
+		// The certificate is already in TLSConfig, loaded at startup.
+		serveFn = func(ln net.Listener) error { return server.ServeTLS(ln, "", "") }
+	}
+	if err := serveFn(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 
 	<-shutdownDone
 	return nil
 }
+
+// clientOptions keeps idle connections to Syncthing open for at least two
+// poll intervals so consecutive polls reuse them.
+func clientOptions(cfg config.Config) syncthing.ClientOptions {
+	return syncthing.ClientOptions{
+		MaxIdleConns:     cfg.STMaxIdleConns,
+		IdleConnTimeout:  max(90*time.Second, 2*cfg.PollInterval),
+		MaxResponseBytes: cfg.STMaxResponseBytes,
+		TLSCertSHA256:    cfg.STTLSCertSHA256,
+		ClientCert:       cfg.STClientCert,
+		ExtraReadPaths:   cfg.STExtraReadPaths,
+	}
+}
+
+// runCheck validates the loaded configuration against the configured Syncthing
+// node and prints a short summary instead of starting the server.
+func runCheck(ctx context.Context, cfg config.Config, out io.Writer) error {
+	if cfg.DemoMode {
+		fmt.Fprintln(out, "configuration OK")
+		fmt.Fprintln(out, "demo mode:  active (SYNCTHING_BASE_URL is not set)")
+		return nil
+	}
+
+	client := syncthing.NewClient(cfg.STBaseURL, cfg.STAPIKey, cfg.STTimeout, cfg.STInsecureSkipVerify, clientOptions(cfg))
+	status, err := client.GetSystemStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("check system status: %w", err)
+	}
+	version, err := client.GetSystemVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("check system version: %w", err)
+	}
+	stConfig, err := client.GetConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("check config: %w", err)
+	}
+
+	fmt.Fprintln(out, "configuration OK")
+	fmt.Fprintf(out, "base URL:   %s\n", cfg.STBaseURL)
+	fmt.Fprintf(out, "device ID:  %s\n", status.MyID)
+	fmt.Fprintf(out, "version:    %s\n", version.Version)
+	fmt.Fprintf(out, "folders:    %d\n", len(stConfig.Folders))
+	return nil
+}
+
+// unixSocketMode lets the socket owner and group (e.g. a reverse proxy) connect.
+const unixSocketMode = 0o660
+
+// listen opens a TCP listener for host:port addresses, or a unix socket for
+// addresses of the form unix:/path/to/sock. The returned cleanup removes the
+// socket file.
+func listen(addr string) (net.Listener, func(), error) {
+	socketPath, isUnix := strings.CutPrefix(addr, "unix:")
+	if !isUnix {
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, nil, err
+		}
+		return ln, func() {}, nil
+	}
+
+	if socketPath == "" {
+		return nil, nil, fmt.Errorf("unix socket path is empty")
+	}
+	if info, err := os.Lstat(socketPath); err == nil {
+		if info.Mode().Type() != fs.ModeSocket {
+			return nil, nil, fmt.Errorf("%s exists and is not a socket", socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return nil, nil, fmt.Errorf("remove stale socket %s: %w", socketPath, err)
+		}
+	}
+
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.Chmod(socketPath, unixSocketMode); err != nil {
+		_ = ln.Close()
+		return nil, nil, fmt.Errorf("chmod socket %s: %w", socketPath, err)
+	}
+
+	return ln, func() {
+		if err := os.Remove(socketPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			slog.Error("failed to remove socket", "path", socketPath, "error", err)
+		}
+	}, nil
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"syncthing-dashboard/internal/collector"
+	"syncthing-dashboard/internal/config"
+	httpapi "syncthing-dashboard/internal/http"
+	"syncthing-dashboard/internal/syncthing"
+)
+
+func startServe(t *testing.T, handler http.Handler, shutdownTimeout time.Duration) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, &http.Server{Handler: handler}, ln, shutdownTimeout)
+	}()
+	return "http://" + ln.Addr().String(), cancel, done
+}
+
+func TestServeDrainsInFlightRequestsOnShutdown(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		_, _ = io.WriteString(w, "done")
+	})
+	url, cancel, done := startServe(t, handler, 2*time.Second)
+
+	type result struct {
+		body string
+		err  error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- result{body: string(body), err: err}
+	}()
+
+	<-started
+	cancel()
+
+	res := <-responses
+	if res.err != nil || res.body != "done" {
+		t.Fatalf("expected in-flight request to complete, got body=%q err=%v", res.body, res.err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("serve returned error: %v", err)
+	}
+}
+
+func TestServePairStopsBothWhenOneFails(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	adminLn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	// A closed listener makes the admin server fail at once.
+	_ = adminLn.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- servePair(context.Background(), &http.Server{Handler: http.NotFoundHandler()}, ln, &http.Server{Handler: http.NotFoundHandler()}, adminLn, time.Second)
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatalf("expected the admin listener error to be returned")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the dashboard server to stop with the admin server")
+	}
+}
+
+func TestServeStopsAfterShutdownTimeout(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	url, cancel, done := startServe(t, handler, 100*time.Millisecond)
+
+	go func() {
+		if resp, err := http.Get(url); err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("serve returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("serve did not return after the shutdown timeout")
+	}
+}
+
+func TestServeNegotiatesTLSWhenConfigured(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "ok")
+		}),
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, server, ln, time.Second)
+	}()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: pool},
+		ForceAttemptHTTP2: true,
+	}}
+	resp, err := client.Get("https://" + ln.Addr().String())
+	if err != nil {
+		t.Fatalf("https request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.TLS == nil || string(body) != "ok" {
+		t.Fatalf("expected TLS response with body ok, got tls=%v body=%q", resp.TLS != nil, body)
+	}
+	if resp.ProtoMajor != 2 {
+		t.Fatalf("expected HTTP/2, got %s", resp.Proto)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("serve returned error: %v", err)
+	}
+}
+
+func TestConcurrentDashboardRequestsDoNotReachSyncthing(t *testing.T) {
+	var upstreamCalls atomic.Int64
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls.Add(1)
+		switch r.URL.Path {
+		case "/rest/system/status":
+			_, _ = w.Write([]byte(`{"myID":"LOCAL-1"}`))
+		case "/rest/config":
+			_, _ = w.Write([]byte(`{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[]}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer upstream.Close()
+
+	client := syncthing.NewClient(upstream.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	svc := collector.New(client, collector.Options{PollInterval: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	svc.Start(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !svc.Ready() {
+		if time.Now().After(deadline) {
+			t.Fatalf("collector did not complete its first poll")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	api := httpapi.New(svc, httpapi.Options{PollInterval: time.Hour})
+	before := upstreamCalls.Load()
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() {
+			rr := httptest.NewRecorder()
+			api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil))
+			if rr.Code != http.StatusOK {
+				t.Errorf("expected 200, got %d", rr.Code)
+			}
+		})
+	}
+	wg.Wait()
+
+	if extra := upstreamCalls.Load() - before; extra != 0 {
+		t.Fatalf("expected dashboard requests to be served from the cached snapshot, got %d extra Syncthing calls", extra)
+	}
+}
+
+func TestRunCheckPrintsSummary(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/system/status":
+			_, _ = w.Write([]byte(`{"myID":"LOCAL-1"}`))
+		case "/rest/system/version":
+			_, _ = w.Write([]byte(`{"version":"v2.0.1"}`))
+		case "/rest/config":
+			_, _ = w.Write([]byte(`{"devices":[],"folders":[{"id":"a"},{"id":"b"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	cfg := config.Config{STBaseURL: upstream.URL, STAPIKey: "key", STTimeout: 2 * time.Second, STMaxResponseBytes: 1 << 20}
+	var out strings.Builder
+	if err := runCheck(context.Background(), cfg, &out); err != nil {
+		t.Fatalf("runCheck returned error: %v", err)
+	}
+	for _, line := range []string{
+		"base URL:   " + upstream.URL,
+		"device ID:  LOCAL-1",
+		"version:    v2.0.1",
+		"folders:    2",
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Fatalf("expected %q in the summary, got:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestRunCheckFailsWhenSyncthingRejects(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer upstream.Close()
+
+	cfg := config.Config{STBaseURL: upstream.URL, STAPIKey: "wrong", STTimeout: 2 * time.Second, STMaxResponseBytes: 1 << 20}
+	var out strings.Builder
+	err := runCheck(context.Background(), cfg, &out)
+	if err == nil || !strings.Contains(err.Error(), "check system status") {
+		t.Fatalf("expected the system status check to fail, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected no summary on failure, got %q", out.String())
+	}
+}
@@ -2,59 +2,297 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"syncthing-dashboard/internal/history"
 	"syncthing-dashboard/internal/model"
+	"syncthing-dashboard/internal/notify"
 	"syncthing-dashboard/internal/syncthing"
 )
 
+// Options tunes collector behavior. Zero values fall back to defaults.
+type Options struct {
+	PollInterval time.Duration
+	// PollTimeout bounds a whole refresh cycle. Defaults to PollInterval.
+	PollTimeout time.Duration
+	// PollJitter offsets each poll by a random duration in [0, PollJitter)
+	// without changing the average interval. Zero disables jitter.
+	PollJitter time.Duration
+	// StaleAfter is the snapshot age after which it is reported as stale.
+	// Defaults to twice PollInterval.
+	StaleAfter time.Duration
+	// CollectRemoteCompletion queries completion for every shared
+	// (folder, remote device) pair, which multiplies the API calls per poll.
+	CollectRemoteCompletion bool
+	// CollectContributors fills each folder's Contributors with per-device
+	// completion. It issues the same per-pair calls as CollectRemoteCompletion,
+	// and both share one round of requests when enabled together.
+	CollectContributors bool
+	// CollectScanProgress reads Syncthing's FolderScanProgress events to fill
+	// ScanProgressPct for scanning folders.
+	CollectScanProgress bool
+	// CollectPending queries devices and folders offered to this node but not
+	// yet accepted, and raises PENDING_DEVICE and PENDING_FOLDER alerts.
+	CollectPending bool
+	// CollectIgnores queries each folder's ignore patterns to fill
+	// IgnorePatterns and IgnoreError, one extra call per folder per poll.
+	CollectIgnores bool
+	// FlapThreshold is how many state changes a folder may make within
+	// FlapWindow before FOLDER_FLAPPING is raised. Zero disables detection.
+	FlapThreshold int
+	FlapWindow    time.Duration
+	// DiskSpace sets when LOW_DISK_SPACE is raised for a folder.
+	DiskSpace model.DiskSpaceThresholds
+	// HistorySize is how many snapshot summaries are retained. Zero disables history.
+	HistorySize int
+	// FolderSort and RemoteSort pick the model sort modes. Empty values sort
+	// folders by label and remotes by name.
+	FolderSort string
+	RemoteSort string
+	// MinVersion raises VERSION_OUTDATED when Syncthing is older. Empty disables the check.
+	MinVersion string
+	// FolderFilter limits which folders, by ID, are collected and shown.
+	FolderFilter model.Filter
+	// DeviceFilter limits which remote devices, by ID or name, are shown.
+	DeviceFilter model.Filter
+	// FolderGroups tags folders, by ID, with a display group.
+	FolderGroups model.FolderGroups
+	// SuppressSeverities drops alerts with these severities from snapshots.
+	// SOURCE_UNREACHABLE and SOURCE_UNAUTHORIZED are never dropped.
+	SuppressSeverities []string
+	// ConnectivityWindow is how long a peak of connected remotes is
+	// remembered for CONNECTIVITY_DEGRADED. Zero uses one hour.
+	ConnectivityWindow time.Duration
+	// MaxAlerts caps the alerts in a snapshot, most severe first, with an
+	// ALERTS_TRUNCATED summary for the rest. Zero keeps every alert.
+	MaxAlerts int
+	// MaxFolders caps how many folders have their status fetched per poll,
+	// rotating through them across polls. Zero fetches every folder.
+	MaxFolders int
+}
+
+// maxConcurrentRequests bounds fan-out calls against the Syncthing API.
+const maxConcurrentRequests = 4
+
 // Collector keeps an in-memory snapshot that is refreshed on an interval.
 type Collector struct {
 	client       *syncthing.Client
 	pollInterval time.Duration
+	pollTimeout  time.Duration
+	pollJitter   time.Duration
+	staleAfter   time.Duration
 
-	mu           sync.RWMutex
-	snapshot     model.DashboardSnapshot
-	hasSnapshot  bool
-	lastGood     model.DashboardSnapshot
-	hasLastGood  bool
-	lastRateAt   time.Time
-	lastInTotal  int64
-	lastOutTotal int64
+	// refreshDone is non-nil while a refresh cycle runs and is closed when
+	// it finishes.
+	refreshMu   sync.Mutex
+	refreshDone chan struct{}
+
+	collectRemoteCompletion bool
+	collectContributors     bool
+	collectPending          bool
+	collectScanProgress     bool
+	collectIgnores          bool
+	scanEventID             int64
+	scanUptime              int64
+	scanProgress            map[string]float64
+	flapThreshold           int
+	flapWindow              time.Duration
+	folderStates            map[string]*folderStateHistory
+	remoteAddresses         map[string]string
+	systemErrors            map[string]time.Time
+	remoteRates             map[string]*rateSample
+	connectivityWindow      time.Duration
+	connectedSamples        []connectedSample
+	diskSpace               model.DiskSpaceThresholds
+	history                 *history.Ring
+	folderSort              string
+	remoteSort              string
+	minVersion              string
+	suppressSeverities      []string
+	maxAlerts               int
+	folderFilter            model.Filter
+	folderGroups            model.FolderGroups
+	deviceFilter            model.Filter
+	maxFolders              int
+	folderCursor            int
+	folderCache             map[string]cachedFolder
+
+	mu          sync.RWMutex
+	snapshot    model.DashboardSnapshot
+	hasSnapshot bool
+	lastGood    model.DashboardSnapshot
+	hasLastGood bool
+	lastRate    rateSample
+	session     sessionCounter
+	// updates wakes streaming clients whenever a snapshot is published.
+	updates notify.Broadcaster
 }
 
-func New(client *syncthing.Client, pollInterval time.Duration) *Collector {
+func New(client *syncthing.Client, opts Options) *Collector {
+	pollTimeout := opts.PollTimeout
+	if pollTimeout <= 0 {
+		pollTimeout = opts.PollInterval
+	}
+	staleAfter := opts.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = 2 * opts.PollInterval
+	}
+	connectivityWindow := opts.ConnectivityWindow
+	if connectivityWindow <= 0 {
+		connectivityWindow = time.Hour
+	}
+
 	return &Collector{
 		client:       client,
-		pollInterval: pollInterval,
+		pollInterval: opts.PollInterval,
+		pollTimeout:  pollTimeout,
+		pollJitter:   opts.PollJitter,
+		staleAfter:   staleAfter,
+
+		collectRemoteCompletion: opts.CollectRemoteCompletion,
+		collectContributors:     opts.CollectContributors,
+		collectPending:          opts.CollectPending,
+		collectScanProgress:     opts.CollectScanProgress,
+		collectIgnores:          opts.CollectIgnores,
+		scanProgress:            make(map[string]float64),
+		flapThreshold:           opts.FlapThreshold,
+		flapWindow:              opts.FlapWindow,
+		folderStates:            make(map[string]*folderStateHistory),
+		remoteAddresses:         make(map[string]string),
+		systemErrors:            make(map[string]time.Time),
+		remoteRates:             make(map[string]*rateSample),
+		connectivityWindow:      connectivityWindow,
+		diskSpace:               opts.DiskSpace,
+		history:                 history.NewRing(opts.HistorySize),
+		folderSort:              opts.FolderSort,
+		remoteSort:              opts.RemoteSort,
+		minVersion:              opts.MinVersion,
+		suppressSeverities:      opts.SuppressSeverities,
+		maxAlerts:               opts.MaxAlerts,
+		folderFilter:            opts.FolderFilter,
+		folderGroups:            opts.FolderGroups,
+		deviceFilter:            opts.DeviceFilter,
+		maxFolders:              opts.MaxFolders,
+		folderCache:             make(map[string]cachedFolder),
+	}
+}
+
+// cachedFolder is a folder's last collected status, kept while MaxFolders
+// skips it. localDirs is kept alongside since FolderStatus does not carry it.
+type cachedFolder struct {
+	status    model.FolderStatus
+	localDirs int64
+}
+
+// foldersToRefresh picks which folders to fetch this poll: all of them without
+// a cap, otherwise the next MaxFolders in round-robin order. It also forgets
+// cached folders that are no longer configured.
+func (c *Collector) foldersToRefresh(folders []syncthing.ConfigFolder) map[string]bool {
+	if c.maxFolders <= 0 || len(folders) <= c.maxFolders {
+		clear(c.folderCache)
+		return nil
+	}
+
+	configured := make(map[string]bool, len(folders))
+	for _, folder := range folders {
+		configured[folder.ID] = true
 	}
+	maps.DeleteFunc(c.folderCache, func(id string, _ cachedFolder) bool {
+		return !configured[id]
+	})
+
+	c.folderCursor %= len(folders)
+	selected := make(map[string]bool, c.maxFolders)
+	for i := range c.maxFolders {
+		selected[folders[(c.folderCursor+i)%len(folders)].ID] = true
+	}
+	c.folderCursor = (c.folderCursor + c.maxFolders) % len(folders)
+	return selected
 }
 
+// folderStateHistory remembers a folder's recent state changes. changes is
+// pruned to the flap window and never holds more than threshold+1 entries.
+type folderStateHistory struct {
+	state   string
+	changes []time.Time
+}
+
+// Start publishes an INITIALIZING placeholder and begins polling in the
+// background, so the dashboard has something to show before the first poll.
 func (c *Collector) Start(ctx context.Context) {
-	c.refresh(ctx, time.Now().UTC())
+	c.setPlaceholder(time.Now().UTC())
 
-	ticker := time.NewTicker(c.pollInterval)
 	go func() {
-		defer ticker.Stop()
+		c.refresh(ctx, time.Now().UTC())
+
+		slot := time.Now()
 		for {
+			var fireAt time.Time
+			slot, fireAt = c.nextPoll(slot, time.Now())
+			timer := time.NewTimer(time.Until(fireAt))
 			select {
 			case <-ctx.Done():
+				timer.Stop()
 				return
-			case <-ticker.C:
+			case <-timer.C:
 				c.refresh(ctx, time.Now().UTC())
 			}
 		}
 	}()
 }
 
+// nextPoll advances the schedule slot by one poll interval and returns it with
+// the time the poll should fire: the slot plus a random offset in
+// [0, pollJitter). Jitter never accumulates because slots advance on a fixed
+// grid. A slot already in the past, after a slow refresh, restarts the grid at
+// now instead of firing a burst of catch-up polls.
+func (c *Collector) nextPoll(slot, now time.Time) (time.Time, time.Time) {
+	slot = slot.Add(c.pollInterval)
+	if slot.Before(now) {
+		slot = now
+	}
+	fireAt := slot
+	if c.pollJitter > 0 {
+		fireAt = fireAt.Add(rand.N(c.pollJitter))
+	}
+	return slot, fireAt
+}
+
+// Ready reports whether a poll has succeeded at least once.
 func (c *Collector) Ready() bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.hasSnapshot
+	return c.hasLastGood
+}
+
+func (c *Collector) setPlaceholder(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.hasSnapshot {
+		return
+	}
+	c.snapshot = model.DashboardSnapshot{
+		GeneratedAt:  now,
+		SourceOnline: false,
+		Alerts: []model.Alert{{
+			Severity:  "info",
+			Code:      "INITIALIZING",
+			Message:   "Waiting for the first poll of the Syncthing API",
+			SubjectID: "syncthing",
+		}},
+	}
+	c.snapshot.UpdateHealth()
+	c.snapshot.SnapshotID = c.snapshot.MeaningfulID()
+	c.hasSnapshot = true
 }
 
 func (c *Collector) Snapshot() (model.DashboardSnapshot, bool) {
@@ -66,7 +304,7 @@ func (c *Collector) Snapshot() (model.DashboardSnapshot, bool) {
 	}
 
 	out := c.snapshot
-	if !out.GeneratedAt.IsZero() && time.Since(out.GeneratedAt) > 2*c.pollInterval {
+	if !out.GeneratedAt.IsZero() && time.Since(out.GeneratedAt) > c.staleAfter {
 		out.Stale = true
 	}
 	if !out.SourceOnline {
@@ -76,13 +314,87 @@ func (c *Collector) Snapshot() (model.DashboardSnapshot, bool) {
 	return out, true
 }
 
+// Updated returns a channel that is closed when the next snapshot is
+// published, whether from a successful poll or the offline fallback.
+func (c *Collector) Updated() <-chan struct{} {
+	return c.updates.Wait()
+}
+
+// History returns up to limit recent snapshot summaries, newest first.
+func (c *Collector) History(limit int) []history.Entry {
+	return c.history.Latest(limit)
+}
+
+// RefreshNow runs a refresh cycle out of band and waits for it. When a cycle
+// is already running, scheduled or not, it waits for that one instead of
+// starting another. The cycle itself is not cancelled with ctx, so a caller
+// giving up does not turn into a failed poll.
+func (c *Collector) RefreshNow(ctx context.Context) error {
+	done, claimed := c.claimRefresh()
+	if claimed {
+		go func() {
+			defer c.releaseRefresh(done)
+			c.update(context.WithoutCancel(ctx), time.Now().UTC())
+		}()
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// claimRefresh marks a refresh cycle as running and returns its done channel.
+// When one is already running it returns that cycle's channel and false.
+func (c *Collector) claimRefresh() (chan struct{}, bool) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	if c.refreshDone != nil {
+		return c.refreshDone, false
+	}
+	c.refreshDone = make(chan struct{})
+	return c.refreshDone, true
+}
+
+func (c *Collector) releaseRefresh(done chan struct{}) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	c.refreshDone = nil
+	close(done)
+}
+
 func (c *Collector) refresh(ctx context.Context, now time.Time) {
+	// A cycle still running when the next one is due wins; the late one is dropped.
+	done, claimed := c.claimRefresh()
+	if !claimed {
+		return
+	}
+	defer c.releaseRefresh(done)
+	c.update(ctx, now)
+}
+
+// update collects a snapshot and publishes it, falling back to the last good
+// one when the poll fails. Callers must hold the refresh claim.
+func (c *Collector) update(ctx context.Context, now time.Time) {
+	if c.pollTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.pollTimeout)
+		defer cancel()
+	}
+
+	started := time.Now()
 	snapshot, err := c.collect(ctx, now)
+	duration := collectDurationMS(time.Since(started))
 	if err == nil {
 		snapshot.GeneratedAt = now
 		snapshot.SourceOnline = true
 		snapshot.SourceError = nil
 		snapshot.Stale = false
+		snapshot.CollectDurationMS = duration
+		snapshot.UpdateHealth()
+		snapshot.SnapshotID = snapshot.MeaningfulID()
 
 		c.mu.Lock()
 		c.snapshot = snapshot
@@ -90,6 +402,8 @@ func (c *Collector) refresh(ctx context.Context, now time.Time) {
 		c.hasSnapshot = true
 		c.hasLastGood = true
 		c.mu.Unlock()
+		c.history.Add(history.FromSnapshot(snapshot))
+		c.updates.Publish()
 		return
 	}
 
@@ -101,6 +415,8 @@ func (c *Collector) refresh(ctx context.Context, now time.Time) {
 		SubjectID: "syncthing",
 	}
 
+	// Deferred first so it runs after the unlock below.
+	defer c.updates.Publish()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.hasLastGood {
@@ -108,20 +424,49 @@ func (c *Collector) refresh(ctx context.Context, now time.Time) {
 		fallback.SourceOnline = false
 		fallback.SourceError = &errText
 		fallback.Stale = true
-		fallback.Alerts = append([]model.Alert{alert}, fallback.Alerts...)
+		fallback.Alerts = withSourceAlert(alert, fallback.Alerts)
+		fallback.CollectDurationMS = duration
+		fallback.UpdateHealth()
+		fallback.SnapshotID = fallback.MeaningfulID()
 		c.snapshot = fallback
 		c.hasSnapshot = true
-		return
+	} else {
+		c.snapshot = model.DashboardSnapshot{
+			GeneratedAt:       now,
+			SourceOnline:      false,
+			SourceError:       &errText,
+			Alerts:            []model.Alert{alert},
+			Stale:             true,
+			CollectDurationMS: duration,
+		}
+		c.snapshot.UpdateHealth()
+		c.snapshot.SnapshotID = c.snapshot.MeaningfulID()
+		c.hasSnapshot = true
 	}
 
-	c.snapshot = model.DashboardSnapshot{
-		GeneratedAt:  now,
-		SourceOnline: false,
-		SourceError:  &errText,
-		Alerts:       []model.Alert{alert},
-		Stale:        true,
+	// The fallback keeps lastGood's timestamp; record when the failure happened.
+	entry := history.FromSnapshot(c.snapshot)
+	entry.GeneratedAt = now
+	c.history.Add(entry)
+}
+
+// collectDurationMS rounds d up so a poll that finished in under a millisecond
+// is not reported as taking no time at all.
+func collectDurationMS(d time.Duration) int64 {
+	return int64((d + time.Millisecond - 1) / time.Millisecond)
+}
+
+// withSourceAlert returns a new slice with alert first, dropping any alert with
+// the same code so consecutive failed refreshes never stack duplicates.
+func withSourceAlert(alert model.Alert, alerts []model.Alert) []model.Alert {
+	out := make([]model.Alert, 0, len(alerts)+1)
+	out = append(out, alert)
+	for _, existing := range alerts {
+		if existing.Code != alert.Code {
+			out = append(out, existing)
+		}
 	}
-	c.hasSnapshot = true
+	return out
 }
 
 func (c *Collector) collect(ctx context.Context, now time.Time) (model.DashboardSnapshot, error) {
@@ -137,6 +482,10 @@ func (c *Collector) collect(ctx context.Context, now time.Time) (model.Dashboard
 	if err != nil {
 		return model.DashboardSnapshot{}, err
 	}
+	systemErrors, err := c.client.GetSystemErrors(ctx)
+	if err != nil {
+		return model.DashboardSnapshot{}, err
+	}
 	deviceStats, err := c.client.GetDeviceStats(ctx)
 	if err != nil {
 		return model.DashboardSnapshot{}, err
@@ -150,8 +499,25 @@ func (c *Collector) collect(ctx context.Context, now time.Time) (model.Dashboard
 		return model.DashboardSnapshot{}, err
 	}
 
-	localDeviceID := status.MyID
-	localDeviceName := localDeviceID
+	// Filtered folders are dropped before any per-folder request is made.
+	cfg.Folders = slices.DeleteFunc(cfg.Folders, func(folder syncthing.ConfigFolder) bool {
+		return !c.folderFilter.Allows(folder.ID)
+	})
+	// Everything below is keyed by folder ID, so a repeated ID would be
+	// collected twice and overwrite its own cached state.
+	var duplicateAlerts []model.Alert
+	cfg.Folders, duplicateAlerts = dedupeFolders(cfg.Folders)
+
+	localDeviceID := strings.TrimSpace(status.MyID)
+	var localIDAlerts []model.Alert
+	if localDeviceID == "" {
+		localDeviceID = inferLocalDeviceID(cfg, connections)
+		localIDAlerts = append(localIDAlerts, localIDUnknownAlert(localDeviceID))
+	}
+	localDeviceName := shortDeviceID(localDeviceID)
+	if localDeviceName == "" {
+		localDeviceName = "This device"
+	}
 	for _, device := range cfg.Devices {
 		if device.DeviceID == localDeviceID {
 			if strings.TrimSpace(device.Name) != "" {
@@ -171,27 +537,68 @@ func (c *Collector) collect(ctx context.Context, now time.Time) (model.Dashboard
 		DownloadBPS: downloadBPS,
 		UploadBPS:   uploadBPS,
 	}
+	sessionIn, sessionOut := c.session.add(connections.Total.InBytesTotal, connections.Total.OutBytesTotal)
+	device.SessionInBytes, device.SessionOutBytes = model.ByteCount(sessionIn), model.ByteCount(sessionOut)
 
 	folders := make([]model.FolderStatus, 0, len(cfg.Folders))
-	var localFilesTotal, localDirsTotal, localBytesTotal int64
+	var localFilesTotal, localDirsTotal int64
+	var localBytesTotal model.ByteCount
+	refresh := c.foldersToRefresh(cfg.Folders)
 	for _, folder := range cfg.Folders {
+		sharedWith := 0
+		for _, device := range folder.Devices {
+			if device.DeviceID != localDeviceID {
+				sharedWith++
+			}
+		}
+
+		// Folders skipped by the cap keep their last collected status; one
+		// not collected yet is left out until its turn.
+		if refresh != nil && !refresh[folder.ID] {
+			if cached, ok := c.folderCache[folder.ID]; ok {
+				cached.status.SharedWith = sharedWith
+				folders = append(folders, cached.status)
+				localFilesTotal += cached.status.LocalFiles
+				localDirsTotal += cached.localDirs
+				localBytesTotal += cached.status.LocalBytes
+			}
+			continue
+		}
+
 		dbStatus, dbErr := c.client.GetDBStatus(ctx, folder.ID)
+		if folderVanished(dbErr) {
+			delete(c.folderCache, folder.ID)
+			continue
+		}
 		if dbErr != nil {
 			return model.DashboardSnapshot{}, fmt.Errorf("get db status for folder %s: %w", folder.ID, dbErr)
 		}
-		completion, completionErr := c.client.GetDBCompletion(ctx, folder.ID)
-		if completionErr != nil {
-			return model.DashboardSnapshot{}, fmt.Errorf("get db completion for folder %s: %w", folder.ID, completionErr)
-		}
-
 		state := strings.TrimSpace(dbStatus.State)
 		if state == "" {
-			state = "unknown"
+			// Syncthing has no database state for the folder yet, e.g. it was
+			// just shared, so its zeros would pass for an empty folder.
+			state = "pending"
 		}
 		if folder.Paused {
 			state = "paused"
 		}
 
+		// A paused folder's completion and need values are stale, and a
+		// pending folder has none yet, so they are neither fetched nor reported.
+		hasProgress := state != "paused" && state != "pending"
+		var completion syncthing.DBCompletionResponse
+		if hasProgress {
+			var completionErr error
+			completion, completionErr = c.client.GetDBCompletion(ctx, folder.ID)
+			if folderVanished(completionErr) {
+				delete(c.folderCache, folder.ID)
+				continue
+			}
+			if completionErr != nil {
+				return model.DashboardSnapshot{}, fmt.Errorf("get db completion for folder %s: %w", folder.ID, completionErr)
+			}
+		}
+
 		needItems := completion.NeedItems
 		if needItems < 0 {
 			needItems = 0
@@ -200,130 +607,592 @@ func (c *Collector) collect(ctx context.Context, now time.Time) (model.Dashboard
 		if needBytes < 0 {
 			needBytes = 0
 		}
+		// The breakdown comes from db/status, so it may lag the db/completion
+		// totals above slightly.
+		var needFiles, needDirectories, needSymlinks, needDeletes int64
+		if hasProgress {
+			needFiles = max(0, dbStatus.NeedFiles)
+			needDirectories = max(0, dbStatus.NeedDirectories)
+			needSymlinks = max(0, dbStatus.NeedSymlinks)
+			needDeletes = max(0, dbStatus.NeedDeletes)
+		}
 		globalBytes := dbStatus.GlobalBytes
 		if completion.GlobalBytes > globalBytes {
 			globalBytes = completion.GlobalBytes
 		}
 		var completionPct *float64
-		if completion.Completion >= 0 && completion.Completion <= 100 {
-			value := completion.Completion
+		if hasProgress && completion.Completion != nil {
+			// Syncthing can report just past the bounds mid-transition, e.g.
+			// 100.0000001, which still means done.
+			value := min(100, max(0, *completion.Completion))
 			completionPct = &value
 		}
 
-		var lastScan *time.Time
+		var lastScan, lastSyncedAt *time.Time
+		var lastSyncedFile *string
 		if fs, ok := folderStats[folder.ID]; ok {
 			parsed := parseSyncthingTime(fs.LastScan)
 			lastScan = parsed
+			if name := strings.TrimSpace(fs.LastFile.Filename); name != "" {
+				lastSyncedFile = &name
+				if at := parseSyncthingTime(fs.LastFile.At); at != nil && !at.IsZero() {
+					lastSyncedAt = at
+				}
+			}
+		}
+
+		var diskFreeBytes *model.ByteCount
+		var diskFreePct *float64
+		if dbStatus.DiskFreeBytes != nil {
+			free := model.ByteCount(max(0, *dbStatus.DiskFreeBytes))
+			diskFreeBytes = &free
+			if dbStatus.DiskTotalBytes != nil && *dbStatus.DiskTotalBytes > 0 {
+				pct := 100 * float64(free) / float64(*dbStatus.DiskTotalBytes)
+				diskFreePct = &pct
+			}
 		}
 
 		label := folder.Label
 		if strings.TrimSpace(label) == "" {
 			label = folder.ID
 		}
+		folderType := strings.TrimSpace(folder.Type)
+		if folderType == "" {
+			folderType = "sendreceive"
+		}
+
+		var watcherError *string
+		if errText := strings.TrimSpace(dbStatus.WatchError); errText != "" {
+			watcherError = &errText
+		}
+
+		var ignorePatterns int
+		var ignoreError *string
+		if c.collectIgnores {
+			ignores, ignoresErr := c.client.GetDBIgnores(ctx, folder.ID)
+			if folderVanished(ignoresErr) {
+				delete(c.folderCache, folder.ID)
+				continue
+			}
+			if ignoresErr != nil {
+				return model.DashboardSnapshot{}, fmt.Errorf("get db ignores for folder %s: %w", folder.ID, ignoresErr)
+			}
+			ignorePatterns = countIgnorePatterns(ignores.Ignore)
+			if errText := strings.TrimSpace(ignores.Error); errText != "" {
+				ignoreError = &errText
+			}
+		}
 
 		folders = append(folders, model.FolderStatus{
 			ID:                folder.ID,
 			Label:             label,
 			Path:              folder.Path,
+			Type:              folderType,
 			State:             state,
 			GlobalFiles:       dbStatus.GlobalFiles,
 			LocalFiles:        dbStatus.LocalFiles,
-			GlobalBytes:       globalBytes,
-			LocalBytes:        dbStatus.LocalBytes,
+			GlobalBytes:       model.ByteCount(globalBytes),
+			LocalBytes:        model.ByteCount(dbStatus.LocalBytes),
 			NeedItems:         needItems,
-			NeedBytes:         needBytes,
+			NeedBytes:         model.ByteCount(needBytes),
+			NeedFiles:         needFiles,
+			NeedDirectories:   needDirectories,
+			NeedSymlinks:      needSymlinks,
+			NeedDeletes:       needDeletes,
 			LocalChangesItems: dbStatus.ReceiveOnlyTotalItems,
+			LocalChangesBytes: model.ByteCount(dbStatus.ReceiveOnlyChangedBytes),
 			CompletionPct:     completionPct,
 			LastScanAt:        lastScan,
+			LastSyncedFile:    lastSyncedFile,
+			LastSyncedAt:      lastSyncedAt,
+			DiskFreeBytes:     diskFreeBytes,
+			DiskFreePct:       diskFreePct,
+			WatcherEnabled:    folder.FSWatcherEnabled,
+			WatcherError:      watcherError,
+			SharedWith:        sharedWith,
+			IgnorePatterns:    ignorePatterns,
+			IgnoreError:       ignoreError,
 		})
+		if refresh != nil {
+			c.folderCache[folder.ID] = cachedFolder{status: folders[len(folders)-1], localDirs: dbStatus.LocalDirectories}
+		}
 
 		localFilesTotal += dbStatus.LocalFiles
 		localDirsTotal += dbStatus.LocalDirectories
-		localBytesTotal += dbStatus.LocalBytes
+		localBytesTotal += model.ByteCount(dbStatus.LocalBytes)
+	}
+	model.SortFolders(folders, c.folderSort)
+	if c.collectScanProgress {
+		if err := c.applyScanProgress(ctx, folders, status.Uptime); err != nil {
+			return model.DashboardSnapshot{}, err
+		}
 	}
-	sort.Slice(folders, func(i, j int) bool {
-		return folders[i].Label < folders[j].Label
-	})
 
 	remotes := make([]model.RemoteDeviceStatus, 0, len(cfg.Devices))
 	for _, deviceCfg := range cfg.Devices {
 		if deviceCfg.DeviceID == localDeviceID {
 			continue
 		}
-		conn := connections.Connections[deviceCfg.DeviceID]
-		deviceStat := deviceStats[deviceCfg.DeviceID]
-
 		name := deviceCfg.Name
 		if strings.TrimSpace(name) == "" {
 			name = deviceCfg.DeviceID
 		}
+		if !c.deviceFilter.Allows(deviceCfg.DeviceID, name) {
+			continue
+		}
+		conn := connections.Connections[deviceCfg.DeviceID]
+		deviceStat := deviceStats[deviceCfg.DeviceID]
+
+		var lastError *string
+		if errText := strings.TrimSpace(conn.Error); errText != "" {
+			lastError = &errText
+		}
 
+		connScheme, connHost, connPort := model.SplitConnAddress(conn.Address)
 		remotes = append(remotes, model.RemoteDeviceStatus{
 			ID:         deviceCfg.DeviceID,
 			Name:       name,
 			Connected:  conn.Connected,
 			Address:    conn.Address,
+			ConnScheme: connScheme,
+			ConnHost:   connHost,
+			ConnPort:   connPort,
 			LastSeenAt: parseSyncthingTime(deviceStat.LastSeen),
+			LastError:  lastError,
 		})
 	}
-	sort.Slice(remotes, func(i, j int) bool {
-		return remotes[i].Name < remotes[j].Name
-	})
+	c.trackRemoteRates(remotes, connections.Connections, now)
+	var sharingAlerts []model.Alert
+	if c.collectRemoteCompletion || c.collectContributors {
+		completions, err := c.collectDeviceCompletions(ctx, cfg.Folders, remotes)
+		if err != nil {
+			return model.DashboardSnapshot{}, err
+		}
+		if c.collectRemoteCompletion {
+			remoteCompletion := remoteCompletions(completions)
+			for i := range remotes {
+				if pct, ok := remoteCompletion[remotes[i].ID]; ok {
+					remotes[i].CompletionPct = &pct
+				}
+			}
+		}
+		if c.collectContributors {
+			contributors := folderContributors(completions, remotes)
+			for i := range folders {
+				folders[i].Contributors = contributors[folders[i].ID]
+			}
+			sharingAlerts = notSharedBackAlerts(completions, folders, remotes)
+		}
+	}
+	model.SortRemotes(remotes, c.remoteSort)
 
 	listenersOK, listenersTotal := serviceHealthCount(status.ConnectionServiceStatus)
 	discoveryOK, discoveryTotal := discoveryHealthCount(status)
 	device.LocalFilesTotal = localFilesTotal
 	device.LocalDirsTotal = localDirsTotal
 	device.LocalBytesTotal = localBytesTotal
+	device.OverallCompletionPct = model.OverallCompletion(folders)
 	device.ListenersOK = listenersOK
 	device.ListenersTotal = listenersTotal
 	device.DiscoveryOK = discoveryOK
 	device.DiscoveryTotal = discoveryTotal
+	device.DiscoveryDetails = discoveryDetails(status)
+	connected := 0
+	for _, remote := range remotes {
+		if remote.Connected {
+			connected++
+		}
+	}
+	device.RemotesConnectedPeak = c.trackConnectedPeak(connected, now)
 
 	alerts := model.DeriveAlerts(remotes, folders)
+	alerts = append(alerts, model.DiskSpaceAlerts(folders, c.diskSpace)...)
+	alerts = append(alerts, model.NotSharedAlerts(folders)...)
+	alerts = append(alerts, model.OverlapAlerts(folders)...)
+	alerts = append(alerts, c.trackFolderFlapping(folders, now)...)
+	alerts = append(alerts, c.trackRemoteAddresses(remotes)...)
+	alerts = append(alerts, model.ConnectivityAlerts(connected, device.RemotesConnectedPeak, localDeviceID)...)
+	alerts = append(alerts, sharingAlerts...)
+	alerts = append(alerts, duplicateAlerts...)
+	alerts = append(alerts, localIDAlerts...)
+	alerts = append(alerts, pausedPeerAlerts(cfg, folders, localDeviceID)...)
+	alerts = append(alerts, c.trackSystemErrors(systemErrors.Errors)...)
+	if c.collectPending {
+		pendingAlerts, err := c.collectPendingAlerts(ctx, cfg.Devices)
+		if err != nil {
+			return model.DashboardSnapshot{}, err
+		}
+		alerts = append(alerts, pendingAlerts...)
+	}
+	alerts = append(alerts, model.VersionAlerts(version.Version, c.minVersion, localDeviceID)...)
+	if len(cfg.Devices) == 0 {
+		alerts = append(alerts, model.Alert{
+			Severity:  "info",
+			Code:      "CONFIG_EMPTY",
+			Message:   "Syncthing has no configuration yet: no devices or folders are defined",
+			SubjectID: localDeviceID,
+		})
+	}
+
+	c.folderGroups.Apply(folders)
 
-	return model.DashboardSnapshot{
+	snapshot := model.DashboardSnapshot{
 		GeneratedAt:  now,
 		SourceOnline: true,
 		SourceError:  nil,
 		Device:       device,
 		Folders:      folders,
 		Remotes:      remotes,
-		Alerts:       alerts,
+		Alerts:       model.TruncateAlerts(model.SuppressAlerts(alerts, c.suppressSeverities), c.maxAlerts),
 		Stale:        false,
-	}, nil
+	}
+	snapshot.PopulateDisplay()
+	snapshot.PopulateAges(now)
+	return snapshot, nil
+}
+
+// deviceCompletion is one remote device's completion of one folder.
+type deviceCompletion struct {
+	folderID   string
+	deviceID   string
+	completion syncthing.DBCompletionResponse
+	vanished   bool
+}
+
+// collectDeviceCompletions queries completion for every unpaused folder and
+// shown remote device it is shared with.
+func (c *Collector) collectDeviceCompletions(ctx context.Context, folders []syncthing.ConfigFolder, remotes []model.RemoteDeviceStatus) ([]deviceCompletion, error) {
+	shown := make(map[string]bool, len(remotes))
+	for _, remote := range remotes {
+		shown[remote.ID] = true
+	}
+	results := make([]deviceCompletion, 0)
+	for _, folder := range folders {
+		if folder.Paused {
+			continue
+		}
+		for _, device := range folder.Devices {
+			if !shown[device.DeviceID] {
+				continue
+			}
+			results = append(results, deviceCompletion{folderID: folder.ID, deviceID: device.DeviceID})
+		}
+	}
+
+	err := forEachBounded(ctx, len(results), maxConcurrentRequests, func(ctx context.Context, i int) error {
+		completion, err := c.client.GetDBDeviceCompletion(ctx, results[i].folderID, results[i].deviceID)
+		if folderVanished(err) {
+			results[i].vanished = true
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("get db completion for folder %s device %s: %w", results[i].folderID, results[i].deviceID, err)
+		}
+		results[i].completion = completion
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(results, func(dc deviceCompletion) bool { return dc.vanished }), nil
+}
+
+// remoteCompletions returns, per remote device, the byte-weighted completion
+// across the folders shared with it.
+func remoteCompletions(completions []deviceCompletion) map[string]float64 {
+	globalBytes := make(map[string]int64)
+	needBytes := make(map[string]int64)
+	for _, dc := range completions {
+		globalBytes[dc.deviceID] += max(0, dc.completion.GlobalBytes)
+		needBytes[dc.deviceID] += max(0, dc.completion.NeedBytes)
+	}
+
+	out := make(map[string]float64, len(globalBytes))
+	for deviceID, global := range globalBytes {
+		if global == 0 {
+			out[deviceID] = 100
+			continue
+		}
+		need := min(needBytes[deviceID], global)
+		out[deviceID] = 100 * float64(global-need) / float64(global)
+	}
+	return out
+}
+
+// folderContributors returns, per folder, each sharing device's progress with
+// the least complete device first. Devices that have not shared the folder
+// back are left out.
+func folderContributors(completions []deviceCompletion, remotes []model.RemoteDeviceStatus) map[string][]model.FolderContributor {
+	names := make(map[string]string, len(remotes))
+	for _, remote := range remotes {
+		names[remote.ID] = remote.Name
+	}
+
+	out := make(map[string][]model.FolderContributor)
+	for _, dc := range completions {
+		if dc.completion.RemoteState == syncthing.RemoteStateNotSharing {
+			continue
+		}
+		var completionPct float64
+		if dc.completion.Completion != nil {
+			completionPct = min(100, max(0, *dc.completion.Completion))
+		}
+		out[dc.folderID] = append(out[dc.folderID], model.FolderContributor{
+			DeviceID:      dc.deviceID,
+			DeviceName:    names[dc.deviceID],
+			CompletionPct: completionPct,
+			NeedBytes:     model.ByteCount(max(0, dc.completion.NeedBytes)),
+		})
+	}
+	for _, contributors := range out {
+		sort.SliceStable(contributors, func(i, j int) bool {
+			if contributors[i].CompletionPct != contributors[j].CompletionPct {
+				return contributors[i].CompletionPct < contributors[j].CompletionPct
+			}
+			return contributors[i].DeviceName < contributors[j].DeviceName
+		})
+	}
+	return out
+}
+
+// notSharedBackAlerts raises FOLDER_NOT_SHARED_BACK for each folder shared
+// with a device that has not shared it back, so it never syncs with it.
+func notSharedBackAlerts(completions []deviceCompletion, folders []model.FolderStatus, remotes []model.RemoteDeviceStatus) []model.Alert {
+	folderLabels := make(map[string]string, len(folders))
+	for _, folder := range folders {
+		folderLabels[folder.ID] = folder.Label
+	}
+	deviceNames := make(map[string]string, len(remotes))
+	for _, remote := range remotes {
+		deviceNames[remote.ID] = remote.Name
+	}
+
+	alerts := make([]model.Alert, 0)
+	for _, dc := range completions {
+		if dc.completion.RemoteState != syncthing.RemoteStateNotSharing {
+			continue
+		}
+		alerts = append(alerts, model.Alert{
+			Severity:  "info",
+			Code:      "FOLDER_NOT_SHARED_BACK",
+			Message:   fmt.Sprintf("Folder %s is shared with %s, which has not shared it back", folderLabels[dc.folderID], deviceNames[dc.deviceID]),
+			SubjectID: dc.folderID,
+		})
+	}
+	return alerts
+}
+
+// pausedPeerAlerts raises FOLDER_PEERS_PAUSED for each folder that is not
+// paused itself but whose every remote device is, so it cannot sync even
+// though it looks active. Folders shared with no remote are left to
+// FOLDER_NOT_SHARED.
+func pausedPeerAlerts(cfg syncthing.ConfigResponse, folders []model.FolderStatus, localDeviceID string) []model.Alert {
+	pausedDevices := make(map[string]string)
+	for _, device := range cfg.Devices {
+		if device.Paused {
+			name := device.Name
+			if strings.TrimSpace(name) == "" {
+				name = device.DeviceID
+			}
+			pausedDevices[device.DeviceID] = name
+		}
+	}
+	if len(pausedDevices) == 0 {
+		return nil
+	}
+	peers := make(map[string][]string, len(cfg.Folders))
+	for _, folder := range cfg.Folders {
+		for _, device := range folder.Devices {
+			if device.DeviceID != localDeviceID {
+				peers[folder.ID] = append(peers[folder.ID], device.DeviceID)
+			}
+		}
+	}
+
+	alerts := make([]model.Alert, 0)
+	for _, folder := range folders {
+		folderPeers := peers[folder.ID]
+		if len(folderPeers) == 0 || strings.EqualFold(folder.State, "paused") {
+			continue
+		}
+		allPaused := true
+		names := make([]string, 0, len(folderPeers))
+		for _, deviceID := range folderPeers {
+			name, paused := pausedDevices[deviceID]
+			allPaused = allPaused && paused
+			names = append(names, name)
+		}
+		if !allPaused {
+			continue
+		}
+		alerts = append(alerts, model.Alert{
+			Severity:  "info",
+			Code:      "FOLDER_PEERS_PAUSED",
+			Message:   fmt.Sprintf("Folder %s is not paused, but every device it is shared with is: %s", folder.Label, strings.Join(names, ", ")),
+			SubjectID: folder.ID,
+		})
+	}
+	return alerts
+}
+
+// countIgnorePatterns counts the .stignore lines that are patterns, leaving
+// out blank lines and // comments. #include lines count as one each.
+func countIgnorePatterns(lines []string) int {
+	count := 0
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "//") {
+			count++
+		}
+	}
+	return count
+}
+
+// folderVanished reports whether err is Syncthing answering 404 for a
+// per-folder db call, which happens when the folder is removed between
+// reading the config and querying it. Such folders are skipped for the poll.
+func folderVanished(err error) bool {
+	var apiErr *syncthing.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
 }
 
+// forEachBounded runs fn for indexes [0, n) with at most limit calls in
+// flight, returning the first error. Remaining work is cancelled on error.
+func forEachBounded(ctx context.Context, n, limit int, fn func(context.Context, int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, max(1, limit))
+	for i := range n {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, i); err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// minRateInterval is the shortest gap between byte-total samples that rates are
+// computed over; shorter gaps turn small deltas into absurd spikes.
+const minRateInterval = 100 * time.Millisecond
+
 func (c *Collector) currentRates(total syncthing.ConnectionTotals, now time.Time) (float64, float64) {
 	if total.BitsPerSecondIn > 0 || total.BitsPerSecondOut > 0 {
 		return total.BitsPerSecondIn / 8, total.BitsPerSecondOut / 8
 	}
+	return c.lastRate.rates(total.InBytesTotal, total.OutBytesTotal, now)
+}
 
-	if c.lastRateAt.IsZero() {
-		c.lastRateAt = now
-		c.lastInTotal = total.InBytesTotal
-		c.lastOutTotal = total.OutBytesTotal
-		return total.BitsPerSecondIn / 8, total.BitsPerSecondOut / 8
-	}
+// rateSample is the previous reading of a pair of cumulative byte counters.
+type rateSample struct {
+	at  time.Time
+	in  int64
+	out int64
+}
 
-	elapsed := now.Sub(c.lastRateAt).Seconds()
-	if elapsed <= 0 {
-		return total.BitsPerSecondIn / 8, total.BitsPerSecondOut / 8
+// rates records the counters read at now and returns the bytes per second in
+// and out since the previous reading. The first reading, one too soon after
+// the previous, and a counter reset all give zero rates.
+func (s *rateSample) rates(in, out int64, now time.Time) (float64, float64) {
+	previous := *s
+	*s = rateSample{at: now, in: in, out: out}
+	if previous.at.IsZero() {
+		return 0, 0
 	}
 
-	inDelta := total.InBytesTotal - c.lastInTotal
-	outDelta := total.OutBytesTotal - c.lastOutTotal
-	c.lastRateAt = now
-	c.lastInTotal = total.InBytesTotal
-	c.lastOutTotal = total.OutBytesTotal
+	// Poll times are wall-clock, so an NTP step backwards can make the gap
+	// negative or tiny. Skip the sample and restart the baseline from it.
+	if now.Sub(previous.at) < minRateInterval {
+		return 0, 0
+	}
+	elapsed := now.Sub(previous.at).Seconds()
 
+	inDelta := in - previous.in
+	outDelta := out - previous.out
 	if inDelta < 0 || outDelta < 0 {
-		return total.BitsPerSecondIn / 8, total.BitsPerSecondOut / 8
+		return 0, 0
 	}
 
 	return float64(inDelta) / elapsed, float64(outDelta) / elapsed
 }
 
+// sessionCounter turns Syncthing's byte totals, which count from its own
+// start, into bytes counted from the first poll.
+type sessionCounter struct {
+	started bool
+	base    [2]int64
+	last    [2]int64
+	carried [2]int64
+}
+
+// add records the totals of a poll and returns the bytes in and out since the
+// first one. Totals going backwards mean Syncthing restarted: what was counted
+// so far is kept and the new totals become the baseline.
+func (s *sessionCounter) add(in, out int64) (int64, int64) {
+	totals := [2]int64{in, out}
+	switch {
+	case !s.started:
+		s.started = true
+		s.base = totals
+	case in < s.last[0] || out < s.last[1]:
+		for i := range totals {
+			s.carried[i] += s.last[i] - s.base[i]
+		}
+		s.base = totals
+	}
+	s.last = totals
+	return s.carried[0] + in - s.base[0], s.carried[1] + out - s.base[1]
+}
+
+// trackRemoteRates sets each remote's transfer rates from its connection byte
+// totals. Samples of remotes no longer shown are dropped, which keeps the map
+// bounded by the configured devices.
+func (c *Collector) trackRemoteRates(remotes []model.RemoteDeviceStatus, connections map[string]syncthing.ConnectionDetails, now time.Time) {
+	seen := make(map[string]struct{}, len(remotes))
+	for i := range remotes {
+		id := remotes[i].ID
+		seen[id] = struct{}{}
+		sample, ok := c.remoteRates[id]
+		if !ok {
+			sample = &rateSample{}
+			c.remoteRates[id] = sample
+		}
+		conn := connections[id]
+		remotes[i].DownloadBPS, remotes[i].UploadBPS = sample.rates(conn.InBytesTotal, conn.OutBytesTotal, now)
+	}
+
+	for id := range c.remoteRates {
+		if _, ok := seen[id]; !ok {
+			delete(c.remoteRates, id)
+		}
+	}
+}
+
 func serviceHealthCount(statusByKey map[string]syncthing.ServiceStatus) (int, int) {
 	total := len(statusByKey)
 	if total == 0 {
@@ -356,6 +1225,403 @@ func discoveryHealthCount(status syncthing.SystemStatusResponse) (int, int) {
 	return status.DiscoveryMethods - errorCount, status.DiscoveryMethods
 }
 
+// discoveryDetails lists discovery methods by name. Without discoveryStatus
+// only the failing methods named in discoveryErrors are known.
+func discoveryDetails(status syncthing.SystemStatusResponse) []model.DiscoveryMethodStatus {
+	details := make([]model.DiscoveryMethodStatus, 0, max(len(status.DiscoveryStatus), len(status.DiscoveryErrors)))
+	if len(status.DiscoveryStatus) > 0 {
+		for name, method := range status.DiscoveryStatus {
+			detail := model.DiscoveryMethodStatus{Name: name, OK: true}
+			if method.Error != nil && strings.TrimSpace(*method.Error) != "" {
+				errText := strings.TrimSpace(*method.Error)
+				detail.OK = false
+				detail.Error = &errText
+			}
+			details = append(details, detail)
+		}
+	} else {
+		for name, errText := range status.DiscoveryErrors {
+			errText = strings.TrimSpace(errText)
+			details = append(details, model.DiscoveryMethodStatus{Name: name, Error: &errText})
+		}
+	}
+	slices.SortFunc(details, func(a, b model.DiscoveryMethodStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return details
+}
+
+// collectPendingAlerts raises PENDING_DEVICE for each device waiting to be
+// accepted and PENDING_FOLDER for each folder offer, in ID order. Offering
+// devices are named from the configured devices when known.
+func (c *Collector) collectPendingAlerts(ctx context.Context, devices []syncthing.ConfigDevice) ([]model.Alert, error) {
+	pendingDevices, err := c.client.GetPendingDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pendingFolders, err := c.client.GetPendingFolders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceNames := make(map[string]string, len(devices))
+	for _, device := range devices {
+		if name := strings.TrimSpace(device.Name); name != "" {
+			deviceNames[device.DeviceID] = name
+		}
+	}
+	deviceLabel := func(id string) string {
+		if name, ok := deviceNames[id]; ok {
+			return name
+		}
+		return shortDeviceID(id)
+	}
+
+	alerts := make([]model.Alert, 0, len(pendingDevices)+len(pendingFolders))
+	for _, id := range slices.Sorted(maps.Keys(pendingDevices)) {
+		pending := pendingDevices[id]
+		name := strings.TrimSpace(pending.Name)
+		if name == "" {
+			name = shortDeviceID(id)
+		}
+		message := fmt.Sprintf("Device %s (%s) wants to connect", name, id)
+		if address := strings.TrimSpace(pending.Address); address != "" {
+			message += " from " + address
+		}
+		alerts = append(alerts, model.Alert{
+			Severity:  "info",
+			Code:      "PENDING_DEVICE",
+			Message:   message,
+			SubjectID: id,
+		})
+	}
+	for _, id := range slices.Sorted(maps.Keys(pendingFolders)) {
+		offers := pendingFolders[id].OfferedBy
+		for _, deviceID := range slices.Sorted(maps.Keys(offers)) {
+			label := strings.TrimSpace(offers[deviceID].Label)
+			if label == "" {
+				label = id
+			}
+			alerts = append(alerts, model.Alert{
+				Severity:  "info",
+				Code:      "PENDING_FOLDER",
+				Message:   fmt.Sprintf("Device %s offers folder %s (%s)", deviceLabel(deviceID), label, id),
+				SubjectID: id,
+			})
+		}
+	}
+	return alerts, nil
+}
+
+// dedupeFolders keeps the first folder with each ID, as Syncthing itself
+// would, and returns a DUPLICATE_FOLDER_ID alert for each ID seen more than
+// once. Syncthing enforces unique IDs, so duplicates point at a proxy or fork
+// rewriting the config.
+func dedupeFolders(folders []syncthing.ConfigFolder) ([]syncthing.ConfigFolder, []model.Alert) {
+	counts := make(map[string]int, len(folders))
+	kept := make([]syncthing.ConfigFolder, 0, len(folders))
+	for _, folder := range folders {
+		counts[folder.ID]++
+		if counts[folder.ID] == 1 {
+			kept = append(kept, folder)
+		}
+	}
+
+	alerts := make([]model.Alert, 0)
+	for _, folder := range kept {
+		if n := counts[folder.ID]; n > 1 {
+			alerts = append(alerts, model.Alert{
+				Severity:  "warn",
+				Code:      "DUPLICATE_FOLDER_ID",
+				Message:   fmt.Sprintf("Folder ID %s appears %d times in the Syncthing config; only %s (%s) is shown", folder.ID, n, folder.Label, folder.Path),
+				SubjectID: folder.ID,
+			})
+		}
+	}
+	return kept, alerts
+}
+
+// criticalSystemErrors are message fragments of Syncthing errors that stop
+// syncing outright rather than degrade it, matched case-insensitively.
+var criticalSystemErrors = []string{
+	"no space left",
+	"insufficient space",
+	"out of disk",
+	"folder marker",
+}
+
+// systemErrorSeverity is critical for errors that stop a folder from syncing,
+// such as a full disk or a missing folder marker, and warn for the rest.
+func systemErrorSeverity(message string) string {
+	lower := strings.ToLower(message)
+	for _, fragment := range criticalSystemErrors {
+		if strings.Contains(lower, fragment) {
+			return "critical"
+		}
+	}
+	return "warn"
+}
+
+// trackSystemErrors returns one SYSTEM_ERROR alert per distinct message.
+// Syncthing keeps reporting an error until it is cleared, often appending it
+// again with a newer timestamp, so each message is remembered across polls
+// with the time it was first reported and its alert stays the same while it
+// persists. Messages no longer reported are forgotten, so a later recurrence
+// alerts afresh.
+func (c *Collector) trackSystemErrors(errs []syncthing.SystemError) []model.Alert {
+	current := make(map[string]time.Time, len(errs))
+	order := make([]string, 0, len(errs))
+	for _, systemErr := range errs {
+		message := strings.TrimSpace(systemErr.Message)
+		if message == "" {
+			continue
+		}
+
+		var when time.Time
+		if parsed := parseSyncthingTime(systemErr.When); parsed != nil {
+			when = *parsed
+		}
+
+		previous, seen := current[message]
+		if !seen {
+			order = append(order, message)
+		}
+		if !seen || (!when.IsZero() && (previous.IsZero() || when.Before(previous))) {
+			current[message] = when
+		}
+	}
+
+	alerts := make([]model.Alert, 0, len(order))
+	for _, message := range order {
+		if first, ok := c.systemErrors[message]; ok {
+			current[message] = first
+		}
+		text := fmt.Sprintf("Syncthing reported an error: %s", message)
+		if when := current[message]; !when.IsZero() {
+			text = fmt.Sprintf("Syncthing reported an error at %s: %s", when.Format(time.RFC3339), message)
+		}
+		alerts = append(alerts, model.Alert{
+			Severity:  systemErrorSeverity(message),
+			Code:      "SYSTEM_ERROR",
+			Message:   text,
+			SubjectID: "syncthing",
+		})
+	}
+	c.systemErrors = current
+	return alerts
+}
+
+// applyScanProgress reads scan progress events since the last poll and sets
+// ScanProgressPct on scanning folders from the latest event for each. Events
+// are read every poll, not just while scanning, so the next scan starts from
+// fresh ones.
+func (c *Collector) applyScanProgress(ctx context.Context, folders []model.FolderStatus, uptime int64) error {
+	// Event IDs start over when Syncthing restarts.
+	if uptime < c.scanUptime {
+		c.scanEventID = 0
+	}
+	c.scanUptime = uptime
+
+	events, err := c.client.GetScanProgressEvents(ctx, c.scanEventID)
+	if err != nil {
+		return fmt.Errorf("get scan progress events: %w", err)
+	}
+	for _, event := range events {
+		c.scanEventID = max(c.scanEventID, event.ID)
+		if event.Data.Total > 0 {
+			c.scanProgress[event.Data.Folder] = min(100, 100*float64(event.Data.Current)/float64(event.Data.Total))
+		}
+	}
+
+	scanning := make(map[string]bool)
+	for i := range folders {
+		if folders[i].State != "scanning" {
+			continue
+		}
+		scanning[folders[i].ID] = true
+		if pct, ok := c.scanProgress[folders[i].ID]; ok {
+			folders[i].ScanProgressPct = &pct
+		}
+	}
+	// Progress of a finished scan must not carry over into the next one.
+	for folderID := range c.scanProgress {
+		if !scanning[folderID] {
+			delete(c.scanProgress, folderID)
+		}
+	}
+	return nil
+}
+
+// trackFolderFlapping records folder state changes and returns a
+// FOLDER_FLAPPING alert for each folder that changed state more than the
+// threshold within the window. History for folders no longer present is
+// dropped.
+func (c *Collector) trackFolderFlapping(folders []model.FolderStatus, now time.Time) []model.Alert {
+	if c.flapThreshold <= 0 || c.flapWindow <= 0 {
+		return nil
+	}
+
+	alerts := make([]model.Alert, 0)
+	seen := make(map[string]struct{}, len(folders))
+	for _, folder := range folders {
+		seen[folder.ID] = struct{}{}
+
+		tracked, ok := c.folderStates[folder.ID]
+		if !ok {
+			c.folderStates[folder.ID] = &folderStateHistory{state: folder.State}
+			continue
+		}
+		if tracked.state != folder.State {
+			tracked.state = folder.State
+			tracked.changes = append(tracked.changes, now)
+		}
+
+		cutoff := now.Add(-c.flapWindow)
+		kept := tracked.changes[:0]
+		for _, changedAt := range tracked.changes {
+			if changedAt.After(cutoff) {
+				kept = append(kept, changedAt)
+			}
+		}
+		if len(kept) > c.flapThreshold+1 {
+			kept = kept[len(kept)-c.flapThreshold-1:]
+		}
+		tracked.changes = kept
+
+		if len(tracked.changes) > c.flapThreshold {
+			alerts = append(alerts, model.Alert{
+				Severity:  "warn",
+				Code:      "FOLDER_FLAPPING",
+				Message:   fmt.Sprintf("Folder %s changed state more than %d times in %s", folder.Label, c.flapThreshold, c.flapWindow),
+				SubjectID: folder.ID,
+			})
+		}
+	}
+
+	for id := range c.folderStates {
+		if _, ok := seen[id]; !ok {
+			delete(c.folderStates, id)
+		}
+	}
+	return alerts
+}
+
+// trackRemoteAddresses remembers the last address of each connected remote and
+// returns a REMOTE_ADDRESS_CHANGED alert for each one that moved since the
+// previous poll. A disconnected remote keeps its last address, and addresses
+// of remotes no longer configured are dropped.
+func (c *Collector) trackRemoteAddresses(remotes []model.RemoteDeviceStatus) []model.Alert {
+	alerts := make([]model.Alert, 0)
+	seen := make(map[string]struct{}, len(remotes))
+	for _, remote := range remotes {
+		seen[remote.ID] = struct{}{}
+		if !remote.Connected || remote.Address == "" {
+			continue
+		}
+
+		previous, ok := c.remoteAddresses[remote.ID]
+		c.remoteAddresses[remote.ID] = remote.Address
+		if ok && previous != remote.Address {
+			alerts = append(alerts, model.Alert{
+				Severity:  "info",
+				Code:      "REMOTE_ADDRESS_CHANGED",
+				Message:   fmt.Sprintf("Remote %s moved from %s to %s", remote.Name, previous, remote.Address),
+				SubjectID: remote.ID,
+			})
+		}
+	}
+
+	for id := range c.remoteAddresses {
+		if _, ok := seen[id]; !ok {
+			delete(c.remoteAddresses, id)
+		}
+	}
+	return alerts
+}
+
+// connectedSample is a count of connected remotes at a point in time.
+type connectedSample struct {
+	at        time.Time
+	connected int
+}
+
+// trackConnectedPeak records connected at now and returns the highest count
+// seen within the connectivity window. Samples are kept in decreasing count
+// order, since a sample followed by a higher one can never be the peak again,
+// so the oldest kept sample is the peak.
+func (c *Collector) trackConnectedPeak(connected int, now time.Time) int {
+	for len(c.connectedSamples) > 0 && c.connectedSamples[len(c.connectedSamples)-1].connected <= connected {
+		c.connectedSamples = c.connectedSamples[:len(c.connectedSamples)-1]
+	}
+	c.connectedSamples = append(c.connectedSamples, connectedSample{at: now, connected: connected})
+
+	cutoff := now.Add(-c.connectivityWindow)
+	drop := 0
+	for drop < len(c.connectedSamples)-1 && c.connectedSamples[drop].at.Before(cutoff) {
+		drop++
+	}
+	c.connectedSamples = c.connectedSamples[drop:]
+	return c.connectedSamples[0].connected
+}
+
+// inferLocalDeviceID guesses this device's ID when system/status leaves it
+// out. Syncthing lists every configured device but itself in
+// system/connections, so a single configured device missing there is taken
+// to be this one; failing that, the one device every folder is shared with.
+// It returns "" when neither is conclusive.
+func inferLocalDeviceID(cfg syncthing.ConfigResponse, connections syncthing.SystemConnectionsResponse) string {
+	var missing []string
+	for _, device := range cfg.Devices {
+		if _, ok := connections.Connections[device.DeviceID]; !ok {
+			missing = append(missing, device.DeviceID)
+		}
+	}
+	if len(missing) == 1 {
+		return missing[0]
+	}
+	if len(cfg.Folders) == 0 {
+		return ""
+	}
+
+	counts := make(map[string]int)
+	for _, folder := range cfg.Folders {
+		for _, device := range folder.Devices {
+			counts[device.DeviceID]++
+		}
+	}
+	var inAll []string
+	for _, device := range cfg.Devices {
+		if counts[device.DeviceID] == len(cfg.Folders) {
+			inAll = append(inAll, device.DeviceID)
+		}
+	}
+	if len(inAll) == 1 {
+		return inAll[0]
+	}
+	return ""
+}
+
+func localIDUnknownAlert(inferredID string) model.Alert {
+	alert := model.Alert{
+		Severity:  "warn",
+		Code:      "LOCAL_ID_UNKNOWN",
+		Message:   "Syncthing did not report this device's ID; it may be listed among the remote devices",
+		SubjectID: "syncthing",
+	}
+	if inferredID != "" {
+		alert.Message = fmt.Sprintf("Syncthing did not report this device's ID; assuming %s from the configuration", shortDeviceID(inferredID))
+		alert.SubjectID = inferredID
+	}
+	return alert
+}
+
+// shortDeviceID returns the first group of a device ID, the same short form
+// the Syncthing GUI shows.
+func shortDeviceID(id string) string {
+	short, _, _ := strings.Cut(strings.TrimSpace(id), "-")
+	return short
+}
+
 func parseSyncthingTime(value string) *time.Time {
 	value = strings.TrimSpace(value)
 	if value == "" {
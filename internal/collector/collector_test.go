@@ -2,8 +2,12 @@ package collector
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -20,7 +24,9 @@ func TestCollectorMapsSnapshotAndAlerts(t *testing.T) {
 		case "/rest/system/version":
 			_, _ = w.Write([]byte(`{"version":"v2.0.1","os":"linux","arch":"amd64"}`))
 		case "/rest/system/connections":
-			_, _ = w.Write([]byte(`{"total":{"bitsPerSecondIn":8000,"bitsPerSecondOut":4000},"connections":{"REMOTE-1":{"address":"tcp://10.0.0.5:22000","connected":false,"inBytesTotal":100,"outBytesTotal":200}}}`))
+			_, _ = w.Write([]byte(`{"total":{"bitsPerSecondIn":8000,"bitsPerSecondOut":4000},"connections":{"REMOTE-1":{"address":"tcp://10.0.0.5:22000","connected":false,"error":"dial tcp 10.0.0.5:22000: i/o timeout","inBytesTotal":100,"outBytesTotal":200}}}`))
+		case "/rest/system/error":
+			_, _ = w.Write([]byte(`{"errors":[{"when":"2026-02-05T19:00:00Z","message":"disk full"},{"when":"2026-02-05T19:05:00Z","message":"disk full"}]}`))
 		case "/rest/stats/device":
 			_, _ = w.Write([]byte(`{"REMOTE-1":{"lastSeen":"2026-02-05T20:00:00Z"}}`))
 		case "/rest/stats/folder":
@@ -43,8 +49,8 @@ func TestCollectorMapsSnapshotAndAlerts(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false)
-	c := New(client, 5*time.Second)
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
 	c.refresh(context.Background(), time.Now().UTC())
 
 	snapshot, ok := c.Snapshot()
@@ -61,6 +67,9 @@ func TestCollectorMapsSnapshotAndAlerts(t *testing.T) {
 	if snapshot.Device.DownloadBPS != 1000 {
 		t.Fatalf("unexpected download rate: %f", snapshot.Device.DownloadBPS)
 	}
+	if snapshot.Device.DownloadDisplay != "1000 B/s" || snapshot.Device.LocalBytesTotalDisplay != "2.0 KiB" {
+		t.Fatalf("unexpected device display fields: %+v", snapshot.Device)
+	}
 	if snapshot.Device.LocalFilesTotal != 20 || snapshot.Device.LocalDirsTotal != 7 || snapshot.Device.LocalBytesTotal != 2048 {
 		t.Fatalf("unexpected local state totals: %+v", snapshot.Device)
 	}
@@ -76,6 +85,9 @@ func TestCollectorMapsSnapshotAndAlerts(t *testing.T) {
 	if snapshot.Folders[0].GlobalBytes != 4096 || snapshot.Folders[0].LocalBytes != 2048 {
 		t.Fatalf("expected folder byte totals to be mapped")
 	}
+	if snapshot.Folders[0].GlobalBytesDisplay != "4.0 KiB" || snapshot.Folders[0].NeedBytesDisplay != "3.0 KiB" {
+		t.Fatalf("unexpected folder display fields: %+v", snapshot.Folders[0])
+	}
 	if snapshot.Folders[0].NeedItems != 12 || snapshot.Folders[0].NeedBytes != 3072 {
 		t.Fatalf("expected completion endpoint to refine need values")
 	}
@@ -88,12 +100,24 @@ func TestCollectorMapsSnapshotAndAlerts(t *testing.T) {
 	if len(snapshot.Remotes) != 1 || snapshot.Remotes[0].Connected {
 		t.Fatalf("expected disconnected remote")
 	}
+	if snapshot.Remotes[0].ConnScheme != "tcp" || snapshot.Remotes[0].ConnHost != "10.0.0.5" || snapshot.Remotes[0].ConnPort != "22000" {
+		t.Fatalf("expected structured remote address, got %+v", snapshot.Remotes[0])
+	}
+	if snapshot.Remotes[0].Address != "tcp://10.0.0.5:22000" {
+		t.Fatalf("expected raw remote address to be kept, got %q", snapshot.Remotes[0].Address)
+	}
+	if snapshot.Remotes[0].LastError == nil || *snapshot.Remotes[0].LastError != "dial tcp 10.0.0.5:22000: i/o timeout" {
+		t.Fatalf("expected remote last error to be mapped, got %v", snapshot.Remotes[0].LastError)
+	}
 
 	hasRemoteAlert := false
 	hasFolderAlert := false
 	for _, alert := range snapshot.Alerts {
 		if alert.Code == "REMOTE_DISCONNECTED" {
 			hasRemoteAlert = true
+			if alert.Message != "Remote device BHS-HOST40 is disconnected: dial tcp 10.0.0.5:22000: i/o timeout" {
+				t.Fatalf("unexpected remote alert message: %q", alert.Message)
+			}
 		}
 		if alert.Code == "FOLDER_OUT_OF_SYNC" {
 			hasFolderAlert = true
@@ -102,11 +126,28 @@ func TestCollectorMapsSnapshotAndAlerts(t *testing.T) {
 	if !hasRemoteAlert || !hasFolderAlert {
 		t.Fatalf("expected both remote and folder alerts, got %+v", snapshot.Alerts)
 	}
+
+	systemErrorAlerts := 0
+	for _, alert := range snapshot.Alerts {
+		if alert.Code != "SYSTEM_ERROR" {
+			continue
+		}
+		systemErrorAlerts++
+		if alert.Message != "Syncthing reported an error at 2026-02-05T19:00:00Z: disk full" {
+			t.Fatalf("unexpected system error alert message: %q", alert.Message)
+		}
+	}
+	if systemErrorAlerts != 1 {
+		t.Fatalf("expected repeated system errors to collapse into one alert, got %d", systemErrorAlerts)
+	}
+	if snapshot.Health != model.HealthDegraded {
+		t.Fatalf("expected degraded health with critical alerts, got %q", snapshot.Health)
+	}
 }
 
 func TestCollectorSourceUnreachableAddsCriticalAlert(t *testing.T) {
-	client := syncthing.NewClient("http://127.0.0.1:1", "key", 100*time.Millisecond, false)
-	c := New(client, 5*time.Second)
+	client := syncthing.NewClient("http://127.0.0.1:1", "key", 100*time.Millisecond, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
 
 	c.refresh(context.Background(), time.Now().UTC())
 	snapshot, ok := c.Snapshot()
@@ -124,8 +165,152 @@ func TestCollectorSourceUnreachableAddsCriticalAlert(t *testing.T) {
 	}
 }
 
+func TestCollectorPublishesOfflineFallback(t *testing.T) {
+	var failing atomic.Bool
+	base := fakeSyncthingHandler(t, nil)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	now := time.Now().UTC()
+
+	updated := c.Updated()
+	c.refresh(context.Background(), now)
+	select {
+	case <-updated:
+	default:
+		t.Fatalf("expected a successful poll to notify waiters")
+	}
+	good, _ := c.Snapshot()
+
+	updated = c.Updated()
+	failing.Store(true)
+	c.refresh(context.Background(), now.Add(time.Second))
+	select {
+	case <-updated:
+	default:
+		t.Fatalf("expected the offline fallback to notify waiters")
+	}
+	fallback, _ := c.Snapshot()
+	if !fallback.GeneratedAt.Equal(good.GeneratedAt) || fallback.SnapshotID == good.SnapshotID {
+		t.Fatalf("expected the fallback to keep GeneratedAt but change SnapshotID, got %s/%s and %s/%s", good.GeneratedAt, good.SnapshotID, fallback.GeneratedAt, fallback.SnapshotID)
+	}
+}
+
+func TestCollectorDedupesSystemErrorsAcrossPolls(t *testing.T) {
+	var systemErrors atomic.Pointer[string]
+	base := fakeSyncthingHandler(t, nil)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/system/error" {
+			_, _ = w.Write([]byte(*systemErrors.Load()))
+			return
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	poll := func(body string) []model.Alert {
+		t.Helper()
+		systemErrors.Store(&body)
+		c.refresh(context.Background(), time.Now().UTC())
+		snapshot, _ := c.Snapshot()
+		var out []model.Alert
+		for _, alert := range snapshot.Alerts {
+			if alert.Code == "SYSTEM_ERROR" {
+				out = append(out, alert)
+			}
+		}
+		return out
+	}
+
+	first := poll(`{"errors":[{"when":"2026-02-05T19:00:00Z","message":"failed to create folder marker"}]}`)
+	// Syncthing reports the error again on its next attempt, and its bounded
+	// error list may have dropped the first report by then.
+	second := poll(`{"errors":[{"when":"2026-02-05T19:10:00Z","message":"failed to create folder marker"}]}`)
+	if len(first) != 1 || len(second) != 1 || second[0] != first[0] {
+		t.Fatalf("expected one unchanged alert across polls, got %+v then %+v", first, second)
+	}
+	if second[0].Severity != "critical" || !strings.Contains(second[0].Message, "2026-02-05T19:00:00Z") {
+		t.Fatalf("expected a critical alert dated from the first report, got %+v", second[0])
+	}
+
+	if cleared := poll(`{"errors":null}`); len(cleared) != 0 {
+		t.Fatalf("expected no alert once errors are cleared, got %+v", cleared)
+	}
+	recurred := poll(`{"errors":[{"when":"2026-02-05T20:00:00Z","message":"failed to create folder marker"}]}`)
+	if len(recurred) != 1 || !strings.Contains(recurred[0].Message, "2026-02-05T20:00:00Z") {
+		t.Fatalf("expected a recurrence after clearing to be dated afresh, got %+v", recurred)
+	}
+
+	if warn := poll(`{"errors":[{"when":"2026-02-05T21:00:00Z","message":"listener failed"}]}`); len(warn) != 1 || warn[0].Severity != "warn" {
+		t.Fatalf("expected other errors to be warnings, got %+v", warn)
+	}
+}
+
+func TestCollectorFallbackKeepsSingleUnreachableAlert(t *testing.T) {
+	var failing atomic.Bool
+	base := fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"desk"}],"folders":[]}`,
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	now := time.Now().UTC()
+	c.refresh(context.Background(), now)
+
+	failing.Store(true)
+	for i := range 5 {
+		c.refresh(context.Background(), now.Add(time.Duration(i+1)*time.Second))
+	}
+
+	snapshot, ok := c.Snapshot()
+	if !ok {
+		t.Fatalf("expected snapshot")
+	}
+	if snapshot.SourceOnline {
+		t.Fatalf("expected source to be offline")
+	}
+
+	unreachable := 0
+	remoteDisconnected := 0
+	for _, alert := range snapshot.Alerts {
+		switch alert.Code {
+		case "SOURCE_UNREACHABLE":
+			unreachable++
+		case "REMOTE_DISCONNECTED":
+			remoteDisconnected++
+		}
+	}
+	if unreachable != 1 {
+		t.Fatalf("expected exactly one SOURCE_UNREACHABLE alert after repeated failures, got %d: %+v", unreachable, snapshot.Alerts)
+	}
+	if remoteDisconnected != 1 {
+		t.Fatalf("expected last good alerts to be kept once, got %d", remoteDisconnected)
+	}
+	if snapshot.Health != model.HealthDown {
+		t.Fatalf("expected down health while serving the last good snapshot offline, got %q", snapshot.Health)
+	}
+}
+
 func TestSnapshotBecomesStaleByAge(t *testing.T) {
-	c := &Collector{pollInterval: 5 * time.Second}
+	c := New(nil, Options{PollInterval: 5 * time.Second})
 	c.snapshot = model.DashboardSnapshot{
 		GeneratedAt:  time.Now().UTC().Add(-11 * time.Second),
 		SourceOnline: true,
@@ -142,6 +327,23 @@ func TestSnapshotBecomesStaleByAge(t *testing.T) {
 	}
 }
 
+func TestSnapshotHonorsConfiguredStaleAfter(t *testing.T) {
+	c := New(nil, Options{PollInterval: 5 * time.Second, StaleAfter: 30 * time.Second})
+	c.hasSnapshot = true
+
+	c.snapshot = model.DashboardSnapshot{GeneratedAt: time.Now().UTC().Add(-25 * time.Second), SourceOnline: true}
+	snapshot, _ := c.Snapshot()
+	if snapshot.Stale {
+		t.Fatalf("expected snapshot inside the stale window to be fresh")
+	}
+
+	c.snapshot = model.DashboardSnapshot{GeneratedAt: time.Now().UTC().Add(-35 * time.Second), SourceOnline: true}
+	snapshot, _ = c.Snapshot()
+	if !snapshot.Stale {
+		t.Fatalf("expected snapshot outside the stale window to be stale")
+	}
+}
+
 func TestCollectorComputesRatesFromConnectionTotals(t *testing.T) {
 	var connectionCalls atomic.Int64
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -157,6 +359,8 @@ func TestCollectorComputesRatesFromConnectionTotals(t *testing.T) {
 			} else {
 				_, _ = w.Write([]byte(`{"total":{"inBytesTotal":1600,"outBytesTotal":2600},"connections":{}}`))
 			}
+		case "/rest/system/error":
+			_, _ = w.Write([]byte(`{"errors":null}`))
 		case "/rest/stats/device":
 			_, _ = w.Write([]byte(`{}`))
 		case "/rest/stats/folder":
@@ -173,8 +377,8 @@ func TestCollectorComputesRatesFromConnectionTotals(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false)
-	c := New(client, 5*time.Second)
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
 	now := time.Now().UTC()
 	c.refresh(context.Background(), now)
 	c.refresh(context.Background(), now.Add(time.Second))
@@ -187,3 +391,1570 @@ func TestCollectorComputesRatesFromConnectionTotals(t *testing.T) {
 		t.Fatalf("expected positive rates from total byte deltas, got down=%f up=%f", snapshot.Device.DownloadBPS, snapshot.Device.UploadBPS)
 	}
 }
+
+func TestCollectorComputesPerRemoteRates(t *testing.T) {
+	var calls atomic.Int64
+	totals := []string{
+		`"REMOTE-1":{"connected":true,"inBytesTotal":1000,"outBytesTotal":5000},"REMOTE-2":{"connected":true,"inBytesTotal":300,"outBytesTotal":0}`,
+		`"REMOTE-1":{"connected":true,"inBytesTotal":3000,"outBytesTotal":6000},"REMOTE-2":{"connected":true,"inBytesTotal":100,"outBytesTotal":50}`,
+	}
+	base := fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"laptop"},{"deviceID":"REMOTE-2","name":"phone"}],"folders":[]}`,
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/system/connections" {
+			entries := totals[min(int(calls.Add(1)-1), len(totals)-1)]
+			_, _ = w.Write([]byte(`{"total":{},"connections":{` + entries + `}}`))
+			return
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	now := time.Now().UTC()
+	c.refresh(context.Background(), now)
+	c.refresh(context.Background(), now.Add(2*time.Second))
+
+	snapshot, _ := c.Snapshot()
+	rates := make(map[string][2]float64)
+	for _, remote := range snapshot.Remotes {
+		rates[remote.ID] = [2]float64{remote.DownloadBPS, remote.UploadBPS}
+	}
+	if got := rates["REMOTE-1"]; got != [2]float64{1000, 500} {
+		t.Fatalf("expected REMOTE-1 rates 1000/500, got %v", got)
+	}
+	// REMOTE-2 reconnected and its counters restarted, like the global totals
+	// after a Syncthing restart.
+	if got := rates["REMOTE-2"]; got != [2]float64{0, 0} {
+		t.Fatalf("expected zero REMOTE-2 rates after a counter reset, got %v", got)
+	}
+}
+
+func TestCollectorCountsSessionBytes(t *testing.T) {
+	var calls atomic.Int64
+	totals := []string{
+		`{"inBytesTotal":1000,"outBytesTotal":2000}`,
+		`{"inBytesTotal":1600,"outBytesTotal":2900}`,
+		// Syncthing restarted and its counters began again.
+		`{"inBytesTotal":100,"outBytesTotal":50}`,
+		`{"inBytesTotal":400,"outBytesTotal":150}`,
+	}
+	base := fakeSyncthingHandler(t, nil)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/system/connections" {
+			total := totals[min(int(calls.Add(1)-1), len(totals)-1)]
+			_, _ = w.Write([]byte(`{"total":` + total + `,"connections":{}}`))
+			return
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	now := time.Now().UTC()
+	want := [][2]model.ByteCount{{0, 0}, {600, 900}, {600, 900}, {900, 1000}}
+	for i, expected := range want {
+		c.refresh(context.Background(), now.Add(time.Duration(i)*time.Second))
+		snapshot, _ := c.Snapshot()
+		if got := [2]model.ByteCount{snapshot.Device.SessionInBytes, snapshot.Device.SessionOutBytes}; got != expected {
+			t.Fatalf("poll %d: expected session bytes %v, got %v", i+1, expected, got)
+		}
+	}
+}
+
+func TestCurrentRatesSkipsTinyAndBackwardIntervals(t *testing.T) {
+	c := New(nil, Options{PollInterval: 5 * time.Second})
+	now := time.Date(2026, 2, 5, 20, 0, 0, 0, time.UTC)
+	sample := func(at time.Time, in, out int64) (float64, float64) {
+		return c.currentRates(syncthing.ConnectionTotals{InBytesTotal: in, OutBytesTotal: out}, at)
+	}
+
+	sample(now, 0, 0)
+	if down, up := sample(now.Add(time.Microsecond), 5_000_000, 5_000_000); down != 0 || up != 0 {
+		t.Fatalf("expected a microsecond interval to be skipped, got down=%f up=%f", down, up)
+	}
+
+	// A clock step backwards restarts the baseline rather than waiting for
+	// the wall clock to catch up with the old sample.
+	stepped := now.Add(-time.Minute)
+	if down, up := sample(stepped, 6_000_000, 6_000_000); down != 0 || up != 0 {
+		t.Fatalf("expected a backwards interval to be skipped, got down=%f up=%f", down, up)
+	}
+	down, up := sample(stepped.Add(time.Second), 6_001_000, 6_002_000)
+	if down != 1000 || up != 2000 {
+		t.Fatalf("expected rates from the restarted baseline, got down=%f up=%f", down, up)
+	}
+}
+
+func TestRefreshDoesNotOverlap(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int64
+	base := fakeSyncthingHandler(t, nil)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/system/status" {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				previous := maxInFlight.Load()
+				if current <= previous || maxInFlight.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.refresh(context.Background(), time.Now().UTC())
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight.Load() != 1 {
+		t.Fatalf("expected refreshes not to overlap, saw %d concurrent collects", maxInFlight.Load())
+	}
+	if !c.Ready() {
+		t.Fatalf("expected a snapshot after refresh")
+	}
+}
+
+func TestRefreshAbandonsCycleAfterPollTimeout(t *testing.T) {
+	base := fakeSyncthingHandler(t, nil)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/system/status" {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(2 * time.Second):
+			}
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 5*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, PollTimeout: 50 * time.Millisecond})
+
+	started := time.Now()
+	c.refresh(context.Background(), time.Now().UTC())
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("expected refresh to stop at the poll timeout, took %s", elapsed)
+	}
+
+	snapshot, ok := c.Snapshot()
+	if !ok {
+		t.Fatalf("expected snapshot")
+	}
+	if snapshot.SourceOnline {
+		t.Fatalf("expected timed-out cycle to mark source offline")
+	}
+}
+
+func TestCollectorAggregatesRemoteCompletion(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"desk"},{"deviceID":"REMOTE-2","name":"attic"}],"folders":[` +
+			`{"id":"app","label":"app","path":"/a","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"},{"deviceID":"REMOTE-2"}]},` +
+			`{"id":"media","label":"media","path":"/m","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"}]},` +
+			`{"id":"old","label":"old","path":"/o","paused":true,"devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-2"}]}]}`,
+		"/rest/db/completion?device=REMOTE-1&folder=app":   `{"completion":100,"needBytes":0,"globalBytes":1000}`,
+		"/rest/db/completion?device=REMOTE-1&folder=media": `{"completion":50,"needBytes":1500,"globalBytes":3000}`,
+		"/rest/db/completion?device=REMOTE-2&folder=app":   `{"completion":40,"needBytes":600,"globalBytes":1000}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, CollectRemoteCompletion: true})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, ok := c.Snapshot()
+	if !ok || !snapshot.SourceOnline {
+		t.Fatalf("expected online snapshot, got %+v", snapshot)
+	}
+
+	byID := make(map[string]model.RemoteDeviceStatus)
+	for _, remote := range snapshot.Remotes {
+		byID[remote.ID] = remote
+	}
+	if pct := byID["REMOTE-1"].CompletionPct; pct == nil || *pct != 62.5 {
+		t.Fatalf("expected REMOTE-1 completion 62.5, got %v", pct)
+	}
+	if pct := byID["REMOTE-2"].CompletionPct; pct == nil || *pct != 40 {
+		t.Fatalf("expected REMOTE-2 completion 40 (paused folder skipped), got %v", pct)
+	}
+}
+
+func TestCollectorReportsFolderContributors(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"desk"},{"deviceID":"REMOTE-2","name":"attic"}],"folders":[` +
+			`{"id":"app","label":"app","path":"/a","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"},{"deviceID":"REMOTE-2"}]},` +
+			`{"id":"solo","label":"solo","path":"/s","devices":[{"deviceID":"LOCAL-1"}]}]}`,
+		"/rest/db/completion?device=REMOTE-1&folder=app": `{"completion":100,"needBytes":0,"globalBytes":1000}`,
+		"/rest/db/completion?device=REMOTE-2&folder=app": `{"completion":40,"needBytes":600,"globalBytes":1000}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, CollectContributors: true})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	byID := make(map[string]model.FolderStatus)
+	for _, folder := range snapshot.Folders {
+		byID[folder.ID] = folder
+	}
+
+	want := []model.FolderContributor{
+		{DeviceID: "REMOTE-2", DeviceName: "attic", CompletionPct: 40, NeedBytes: 600},
+		{DeviceID: "REMOTE-1", DeviceName: "desk", CompletionPct: 100, NeedBytes: 0},
+	}
+	if got := byID["app"].Contributors; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected contributors for app:\n got %+v\nwant %+v", got, want)
+	}
+	if got := byID["solo"].Contributors; len(got) != 0 {
+		t.Fatalf("expected no contributors for an unshared folder, got %+v", got)
+	}
+	for _, remote := range snapshot.Remotes {
+		if remote.CompletionPct != nil {
+			t.Fatalf("expected remote completion to stay off, got %v for %s", *remote.CompletionPct, remote.ID)
+		}
+	}
+}
+
+func TestCollectorAlertsFolderNotSharedBack(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"desk"},{"deviceID":"REMOTE-2","name":"attic"}],"folders":[` +
+			`{"id":"app","label":"App","path":"/a","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"},{"deviceID":"REMOTE-2"}]}]}`,
+		"/rest/db/completion?device=REMOTE-1&folder=app": `{"completion":100,"needBytes":0,"globalBytes":1000,"remoteState":"valid"}`,
+		"/rest/db/completion?device=REMOTE-2&folder=app": `{"completion":0,"needBytes":1000,"globalBytes":1000,"remoteState":"notSharing"}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, CollectContributors: true})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	var notShared []model.Alert
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "FOLDER_NOT_SHARED_BACK" {
+			notShared = append(notShared, alert)
+		}
+	}
+	if len(notShared) != 1 || notShared[0].Severity != "info" || notShared[0].SubjectID != "app" {
+		t.Fatalf("expected one FOLDER_NOT_SHARED_BACK alert for app, got %+v", snapshot.Alerts)
+	}
+	if !strings.Contains(notShared[0].Message, "App") || !strings.Contains(notShared[0].Message, "attic") {
+		t.Fatalf("expected alert to name the folder and device, got %q", notShared[0].Message)
+	}
+	contributors := snapshot.Folders[0].Contributors
+	if len(contributors) != 1 || contributors[0].DeviceID != "REMOTE-1" {
+		t.Fatalf("expected the non-sharing device to be left out of contributors, got %+v", contributors)
+	}
+}
+
+func TestCollectorSkipsRemoteCompletionByDefault(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"desk"}],"folders":[{"id":"app","label":"app","path":"/a","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"}]}]}`,
+		"/rest/db/completion?device=REMOTE-1&folder=app": `{"completion":"not-a-number"}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	if !snapshot.SourceOnline {
+		t.Fatalf("expected per-device completion not to be requested, got error %v", *snapshot.SourceError)
+	}
+	if snapshot.Remotes[0].CompletionPct != nil {
+		t.Fatalf("expected no remote completion when collection is disabled")
+	}
+}
+
+func TestCollectorReportsEmptyConfig(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/system/status": `{"myID":"ABCDEF1-GHIJKL2-MNOPQR3","uptime":5}`,
+		"/rest/config":        `{"devices":[],"folders":[]}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, ok := c.Snapshot()
+	if !ok || !snapshot.SourceOnline {
+		t.Fatalf("expected online snapshot")
+	}
+	if snapshot.Device.ID != "ABCDEF1-GHIJKL2-MNOPQR3" || snapshot.Device.Name != "ABCDEF1" {
+		t.Fatalf("expected device identity from status, got %+v", snapshot.Device)
+	}
+	if len(snapshot.Folders) != 0 || len(snapshot.Remotes) != 0 {
+		t.Fatalf("expected no folders or remotes")
+	}
+	if len(snapshot.Alerts) != 1 || snapshot.Alerts[0].Code != "CONFIG_EMPTY" || snapshot.Alerts[0].Severity != "info" {
+		t.Fatalf("expected a single CONFIG_EMPTY info alert, got %+v", snapshot.Alerts)
+	}
+	if snapshot.Health != model.HealthOK {
+		t.Fatalf("expected ok health with only an info alert, got %q", snapshot.Health)
+	}
+}
+
+func TestCollectorReportsFolderFlapping(t *testing.T) {
+	var calls atomic.Int64
+	states := []string{"syncing", "error", "syncing", "error", "syncing"}
+	base := fakeSyncthingHandler(t, nil)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/db/status" {
+			state := states[int(calls.Add(1)-1)%len(states)]
+			_, _ = w.Write([]byte(`{"globalBytes":1000,"localBytes":1000,"state":"` + state + `"}`))
+			return
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, FlapThreshold: 3, FlapWindow: time.Hour})
+
+	hasFlapping := func() bool {
+		snapshot, _ := c.Snapshot()
+		for _, alert := range snapshot.Alerts {
+			if alert.Code == "FOLDER_FLAPPING" && alert.SubjectID == "app" {
+				return true
+			}
+		}
+		return false
+	}
+
+	now := time.Now().UTC()
+	for i := range 4 {
+		c.refresh(context.Background(), now.Add(time.Duration(i)*time.Minute))
+		if hasFlapping() {
+			t.Fatalf("expected no flapping alert after %d state changes", i)
+		}
+	}
+
+	c.refresh(context.Background(), now.Add(4*time.Minute))
+	if !hasFlapping() {
+		t.Fatalf("expected flapping alert once state changes exceed the threshold")
+	}
+	if got := len(c.folderStates["app"].changes); got > 4 {
+		t.Fatalf("expected bounded state history, got %d entries", got)
+	}
+}
+
+func TestCollectorForgetsFlapHistoryForRemovedFolders(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[]}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, FlapThreshold: 3, FlapWindow: time.Hour})
+	c.folderStates["gone"] = &folderStateHistory{state: "idle"}
+
+	c.refresh(context.Background(), time.Now().UTC())
+	if _, ok := c.folderStates["gone"]; ok {
+		t.Fatalf("expected history for removed folder to be dropped")
+	}
+}
+
+func TestCollectorReportsRemoteAddressChange(t *testing.T) {
+	var calls atomic.Int64
+	addresses := []string{"tcp://10.0.0.5:22000", "relay://203.0.113.9:22067"}
+	base := fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"laptop"}],"folders":[]}`,
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/system/connections" {
+			address := addresses[min(int(calls.Add(1)-1), len(addresses)-1)]
+			_, _ = w.Write([]byte(`{"total":{},"connections":{"REMOTE-1":{"address":"` + address + `","connected":true}}}`))
+			return
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+
+	addressAlerts := func() []model.Alert {
+		snapshot, _ := c.Snapshot()
+		var out []model.Alert
+		for _, alert := range snapshot.Alerts {
+			if alert.Code == "REMOTE_ADDRESS_CHANGED" {
+				out = append(out, alert)
+			}
+		}
+		return out
+	}
+
+	now := time.Now().UTC()
+	c.refresh(context.Background(), now)
+	if got := addressAlerts(); len(got) != 0 {
+		t.Fatalf("expected no address alert on the initial poll, got %+v", got)
+	}
+
+	c.refresh(context.Background(), now.Add(time.Minute))
+	got := addressAlerts()
+	if len(got) != 1 || got[0].SubjectID != "REMOTE-1" || got[0].Severity != "info" {
+		t.Fatalf("expected one REMOTE_ADDRESS_CHANGED info alert, got %+v", got)
+	}
+	if !strings.Contains(got[0].Message, "tcp://10.0.0.5:22000") || !strings.Contains(got[0].Message, "relay://203.0.113.9:22067") {
+		t.Fatalf("expected old and new address in message, got %q", got[0].Message)
+	}
+
+	c.refresh(context.Background(), now.Add(2*time.Minute))
+	if got := addressAlerts(); len(got) != 0 {
+		t.Fatalf("expected alert to clear once the address is stable, got %+v", got)
+	}
+}
+
+func TestCollectorAppliesFolderFilter(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+	base := fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[` +
+			`{"id":"team-design","label":"design","path":"/d"},` +
+			`{"id":"team-secret","label":"secret","path":"/s"},` +
+			`{"id":"personal","label":"personal","path":"/p"}]}`,
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if folder := r.URL.Query().Get("folder"); folder != "" {
+			mu.Lock()
+			requested[folder] = true
+			mu.Unlock()
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{
+		PollInterval: 5 * time.Second,
+		FolderFilter: model.Filter{Include: []string{"team-*"}, Exclude: []string{"team-secret"}},
+	})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	if len(snapshot.Folders) != 1 || snapshot.Folders[0].ID != "team-design" {
+		t.Fatalf("expected only team-design to be shown, got %+v", snapshot.Folders)
+	}
+	if snapshot.Device.LocalBytesTotal != snapshot.Folders[0].LocalBytes {
+		t.Fatalf("expected totals to cover only included folders, got %d", snapshot.Device.LocalBytesTotal)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if requested["team-secret"] || requested["personal"] {
+		t.Fatalf("expected filtered folders not to be collected, requested %v", requested)
+	}
+}
+
+func TestCollectorTagsFolderGroups(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[` +
+			`{"id":"folder-taxes","label":"taxes","path":"/t"},` +
+			`{"id":"folder-music","label":"music","path":"/m"},` +
+			`{"id":"folder-scratch","label":"scratch","path":"/s"}]}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{
+		PollInterval: 5 * time.Second,
+		FolderGroups: model.FolderGroups{"folder-taxes": "backups", "folder-music": "media"},
+	})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	groups := make(map[string]string)
+	for _, folder := range snapshot.Folders {
+		groups[folder.ID] = folder.Group
+	}
+	want := map[string]string{"folder-taxes": "backups", "folder-music": "media", "folder-scratch": ""}
+	if !reflect.DeepEqual(groups, want) {
+		t.Fatalf("unexpected folder groups: %v", groups)
+	}
+}
+
+func TestCollectorExcludedDeviceRaisesNoAlert(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"old-phone"},{"deviceID":"REMOTE-2","name":"laptop"}],"folders":[]}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{
+		PollInterval: 5 * time.Second,
+		DeviceFilter: model.Filter{Exclude: []string{"old-*"}},
+	})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	if len(snapshot.Remotes) != 1 || snapshot.Remotes[0].ID != "REMOTE-2" {
+		t.Fatalf("expected only the laptop remote, got %+v", snapshot.Remotes)
+	}
+	for _, alert := range snapshot.Alerts {
+		if alert.SubjectID == "REMOTE-1" {
+			t.Fatalf("expected no alert for excluded device, got %+v", alert)
+		}
+	}
+}
+
+func TestCollectorReportsPendingDevicesAndFolders(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/cluster/pending/devices": `{"NEWDEV1-AAAAAAA":{"name":"new-laptop","address":"192.168.1.20:22000"}}`,
+		"/rest/cluster/pending/folders": `{"photos":{"offeredBy":{"REMOTE-1":{"label":"Photos"}}}}`,
+		"/rest/config":                  `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"backup"}],"folders":[]}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, CollectPending: true})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	found := make(map[string]model.Alert)
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "PENDING_DEVICE" || alert.Code == "PENDING_FOLDER" {
+			found[alert.Code] = alert
+		}
+	}
+	device, folder := found["PENDING_DEVICE"], found["PENDING_FOLDER"]
+	if device.SubjectID != "NEWDEV1-AAAAAAA" || !strings.Contains(device.Message, "new-laptop") || !strings.Contains(device.Message, "192.168.1.20:22000") {
+		t.Fatalf("unexpected PENDING_DEVICE alert: %+v", device)
+	}
+	if folder.SubjectID != "photos" || !strings.Contains(folder.Message, "Photos") || !strings.Contains(folder.Message, "backup") {
+		t.Fatalf("unexpected PENDING_FOLDER alert: %+v", folder)
+	}
+}
+
+func TestCollectorMapsFolderTypeAndLocalChanges(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[` +
+			`{"id":"backup","label":"backup","path":"/b","type":"receiveonly"},` +
+			`{"id":"docs","label":"docs","path":"/d","type":"sendreceive"},` +
+			`{"id":"legacy","label":"legacy","path":"/l"}]}`,
+		"/rest/db/status": `{"globalBytes":1000,"localBytes":1000,"receiveOnlyTotalItems":4,"receiveOnlyChangedBytes":2048,"state":"idle"}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	types := make(map[string]string)
+	for _, folder := range snapshot.Folders {
+		types[folder.ID] = folder.Type
+		if folder.LocalChangesBytes != 2048 {
+			t.Fatalf("expected local changes bytes to map from db/status, got %d for %s", folder.LocalChangesBytes, folder.ID)
+		}
+	}
+	if types["backup"] != "receiveonly" || types["docs"] != "sendreceive" || types["legacy"] != "sendreceive" {
+		t.Fatalf("unexpected folder types: %+v", types)
+	}
+
+	localChangeAlerts := 0
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "FOLDER_LOCAL_CHANGES" {
+			localChangeAlerts++
+			if alert.SubjectID != "backup" {
+				t.Fatalf("expected local change alert only for receive-only folder, got %s", alert.SubjectID)
+			}
+			if !strings.Contains(alert.Message, "2.0 KiB") {
+				t.Fatalf("expected local change bytes in alert message, got %q", alert.Message)
+			}
+		}
+	}
+	if localChangeAlerts != 1 {
+		t.Fatalf("expected one FOLDER_LOCAL_CHANGES alert, got %d", localChangeAlerts)
+	}
+}
+
+func TestCollectorReportsFailedWatcher(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[` +
+			`{"id":"watched","label":"watched","path":"/w","fsWatcherEnabled":true},` +
+			`{"id":"unwatched","label":"unwatched","path":"/u","fsWatcherEnabled":false},` +
+			`{"id":"healthy","label":"healthy","path":"/h","fsWatcherEnabled":true}]}`,
+		"/rest/db/status?folder=watched":   `{"state":"idle","watchError":"too many open files"}`,
+		"/rest/db/status?folder=unwatched": `{"state":"idle","watchError":"too many open files"}`,
+		"/rest/db/status?folder=healthy":   `{"state":"idle","watchError":""}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	for _, folder := range snapshot.Folders {
+		if folder.ID == "watched" && (!folder.WatcherEnabled || folder.WatcherError == nil || *folder.WatcherError != "too many open files") {
+			t.Fatalf("expected watcher state to map from config and db/status, got %+v", folder)
+		}
+		if folder.ID == "healthy" && folder.WatcherError != nil {
+			t.Fatalf("expected no watcher error for healthy folder")
+		}
+	}
+
+	var failed []string
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "WATCHER_FAILED" {
+			if alert.Severity != "warn" {
+				t.Fatalf("expected WATCHER_FAILED to be a warning, got %q", alert.Severity)
+			}
+			failed = append(failed, alert.SubjectID)
+		}
+	}
+	if len(failed) != 1 || failed[0] != "watched" {
+		t.Fatalf("expected WATCHER_FAILED only for the watched folder, got %v", failed)
+	}
+}
+
+func TestCollectorMapsLastSyncedFile(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[` +
+			`{"id":"docs","label":"docs","path":"/d"},{"id":"fresh","label":"fresh","path":"/f"}]}`,
+		"/rest/stats/folder": `{"docs":{"lastFile":{"at":"2026-02-05T20:07:00Z","filename":"report.pdf"}},` +
+			`"fresh":{"lastFile":{"at":"0001-01-01T00:00:00Z","filename":""}}}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	for _, folder := range snapshot.Folders {
+		switch folder.ID {
+		case "docs":
+			if folder.LastSyncedFile == nil || *folder.LastSyncedFile != "report.pdf" ||
+				folder.LastSyncedAt == nil || !folder.LastSyncedAt.Equal(time.Date(2026, 2, 5, 20, 7, 0, 0, time.UTC)) {
+				t.Fatalf("unexpected last synced file for docs: %v %v", folder.LastSyncedFile, folder.LastSyncedAt)
+			}
+		case "fresh":
+			if folder.LastSyncedFile != nil || folder.LastSyncedAt != nil {
+				t.Fatalf("expected no last synced file for a folder that never synced")
+			}
+		}
+	}
+}
+
+func TestCollectorReportsLowDiskSpace(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[` +
+			`{"id":"full","label":"full","path":"/f"},{"id":"roomy","label":"roomy","path":"/r"},{"id":"plain","label":"plain","path":"/p"}]}`,
+		"/rest/db/status?folder=full":  `{"state":"idle","diskFreeBytes":200,"diskTotalBytes":10000}`,
+		"/rest/db/status?folder=roomy": `{"state":"idle","diskFreeBytes":5000,"diskTotalBytes":10000}`,
+		"/rest/db/status?folder=plain": `{"state":"idle"}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, DiskSpace: model.DiskSpaceThresholds{MinFreePct: 5}})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	byID := make(map[string]model.FolderStatus)
+	for _, folder := range snapshot.Folders {
+		byID[folder.ID] = folder
+	}
+	if free := byID["full"].DiskFreeBytes; free == nil || *free != 200 {
+		t.Fatalf("expected disk free bytes to be mapped, got %v", free)
+	}
+	if pct := byID["full"].DiskFreePct; pct == nil || *pct != 2 {
+		t.Fatalf("expected disk free pct of 2, got %v", pct)
+	}
+	if byID["plain"].DiskFreeBytes != nil || byID["plain"].DiskFreePct != nil {
+		t.Fatalf("expected missing disk info to stay nil")
+	}
+
+	lowDisk := make([]string, 0)
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "LOW_DISK_SPACE" {
+			lowDisk = append(lowDisk, alert.SubjectID)
+		}
+	}
+	if len(lowDisk) != 1 || lowDisk[0] != "full" {
+		t.Fatalf("expected LOW_DISK_SPACE only for the full folder, got %v", lowDisk)
+	}
+}
+
+// fakeSyncthingHandler serves a minimal healthy node with one idle folder.
+// Entries in responses override the body for a path, or for path?query when
+// the key includes a query string.
+func fakeSyncthingHandler(t *testing.T, responses map[string]string) http.HandlerFunc {
+	t.Helper()
+
+	defaults := map[string]string{
+		"/rest/system/status":      `{"myID":"LOCAL-1","uptime":120}`,
+		"/rest/system/version":     `{"version":"v2.0.1","os":"linux","arch":"amd64"}`,
+		"/rest/system/connections": `{"total":{},"connections":{}}`,
+		"/rest/system/error":       `{"errors":null}`,
+		"/rest/stats/device":       `{}`,
+		"/rest/stats/folder":       `{}`,
+		"/rest/config":             `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[{"id":"app","label":"app","path":"/mnt/vault/app","paused":false}]}`,
+		"/rest/db/status":          `{"globalFiles":1,"localFiles":1,"localDirectories":1,"globalBytes":1000,"localBytes":1000,"state":"idle"}`,
+		"/rest/db/completion":      `{"completion":100,"needBytes":0,"needItems":0,"globalBytes":1000}`,
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if body, ok := responses[r.URL.Path+"?"+r.URL.RawQuery]; ok {
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		if body, ok := responses[r.URL.Path]; ok {
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		if body, ok := defaults[r.URL.Path]; ok {
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		t.Errorf("unexpected path: %s", r.URL.Path)
+		http.NotFound(w, r)
+	}
+}
+
+func TestSnapshotReturnsInitializingPlaceholderBeforeFirstPoll(t *testing.T) {
+	release := make(chan struct{})
+	base := fakeSyncthingHandler(t, nil)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		base(w, r)
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Start(ctx)
+
+	snapshot, ok := c.Snapshot()
+	if !ok {
+		t.Fatalf("expected placeholder snapshot before the first poll")
+	}
+	if snapshot.SourceOnline {
+		t.Fatalf("expected source_online=false while initializing")
+	}
+	if len(snapshot.Alerts) != 1 || snapshot.Alerts[0].Code != "INITIALIZING" || snapshot.Alerts[0].Severity != "info" {
+		t.Fatalf("expected a single INITIALIZING info alert, got %+v", snapshot.Alerts)
+	}
+	if c.Ready() {
+		t.Fatalf("expected collector not to be ready before the first poll")
+	}
+}
+
+func TestNextPollJittersWithinBoundsWithoutDrift(t *testing.T) {
+	interval := time.Second
+	jitter := 200 * time.Millisecond
+	c := New(nil, Options{PollInterval: interval, PollJitter: jitter})
+
+	start := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
+	slot := start
+	var previous time.Time
+	distinctGaps := make(map[time.Duration]bool)
+	const cycles = 200
+	for i := 0; i < cycles; i++ {
+		var fireAt time.Time
+		// Each refresh completes right when it fires.
+		slot, fireAt = c.nextPoll(slot, previous)
+		if !previous.IsZero() {
+			gap := fireAt.Sub(previous)
+			if gap < interval-jitter || gap > interval+jitter {
+				t.Fatalf("poll gap %s outside %s±%s", gap, interval, jitter)
+			}
+			distinctGaps[gap] = true
+		}
+		previous = fireAt
+	}
+
+	if len(distinctGaps) < 2 {
+		t.Fatalf("expected jittered polls to vary, got gaps %v", distinctGaps)
+	}
+	if want := start.Add(cycles * interval); !slot.Equal(want) {
+		t.Fatalf("expected schedule to stay on the %s grid, slot at %s, want %s", interval, slot, want)
+	}
+}
+
+func TestRefreshRecordsCollectDuration(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, nil))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, ok := c.Snapshot()
+	if !ok || !snapshot.SourceOnline {
+		t.Fatalf("expected online snapshot")
+	}
+	if snapshot.CollectDurationMS <= 0 {
+		t.Fatalf("expected a positive collect duration, got %d", snapshot.CollectDurationMS)
+	}
+}
+
+func TestCollectorSnapshotIsNotDemo(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, nil))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, ok := c.Snapshot()
+	if !ok || snapshot.Demo {
+		t.Fatalf("expected a snapshot with demo=false, got ok=%v demo=%v", ok, snapshot.Demo)
+	}
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "DEMO_MODE" {
+			t.Fatalf("expected no DEMO_MODE alert from the real collector")
+		}
+	}
+}
+
+func TestCollectorSetsSnapshotID(t *testing.T) {
+	var down atomic.Bool
+	base := fakeSyncthingHandler(t, nil)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	now := time.Now().UTC()
+	c.refresh(context.Background(), now)
+	first, _ := c.Snapshot()
+	c.refresh(context.Background(), now.Add(5*time.Second))
+	second, _ := c.Snapshot()
+	if first.SnapshotID == "" || first.SnapshotID != second.SnapshotID {
+		t.Fatalf("expected an unchanged poll to keep the snapshot ID, got %q then %q", first.SnapshotID, second.SnapshotID)
+	}
+
+	down.Store(true)
+	c.refresh(context.Background(), now.Add(10*time.Second))
+	offline, _ := c.Snapshot()
+	if offline.SnapshotID == "" || offline.SnapshotID == first.SnapshotID {
+		t.Fatalf("expected going offline to change the snapshot ID, got %q", offline.SnapshotID)
+	}
+}
+
+func TestCollectorMarksFolderWithoutStatusPending(t *testing.T) {
+	var completionRequests atomic.Int32
+	base := fakeSyncthingHandler(t, map[string]string{
+		"/rest/config":    `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[{"id":"fresh","label":"Fresh","path":"/mnt/vault/fresh","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"}]}]}`,
+		"/rest/db/status": `{"globalFiles":0,"localFiles":0,"globalBytes":0,"localBytes":0,"state":""}`,
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/db/completion" {
+			completionRequests.Add(1)
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	if len(snapshot.Folders) != 1 {
+		t.Fatalf("expected one folder, got %+v", snapshot.Folders)
+	}
+	folder := snapshot.Folders[0]
+	if folder.State != "pending" || folder.CompletionPct != nil {
+		t.Fatalf("expected pending folder without completion, got state=%q completion=%v", folder.State, folder.CompletionPct)
+	}
+	if completionRequests.Load() != 0 {
+		t.Fatalf("expected no completion request for a pending folder")
+	}
+	if len(snapshot.Alerts) != 1 || snapshot.Alerts[0].Code != "FOLDER_PENDING" || snapshot.Alerts[0].Severity != "info" {
+		t.Fatalf("expected a FOLDER_PENDING info alert, got %+v", snapshot.Alerts)
+	}
+	if snapshot.Health != model.HealthOK {
+		t.Fatalf("expected a pending folder not to degrade health, got %q", snapshot.Health)
+	}
+}
+
+func TestCollectorReportsScanProgressOnlyWhileScanning(t *testing.T) {
+	var sinceSeen []string
+	base := fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[` +
+			`{"id":"scan","label":"scan","path":"/a"},{"id":"calm","label":"calm","path":"/b"}]}`,
+		"/rest/db/status?folder=scan": `{"globalFiles":1,"localFiles":1,"globalBytes":1000,"localBytes":1000,"state":"scanning"}`,
+		"/rest/db/status?folder=calm": `{"globalFiles":1,"localFiles":1,"globalBytes":1000,"localBytes":1000,"state":"idle"}`,
+		"/rest/events": `[` +
+			`{"id":5,"type":"FolderScanProgress","data":{"folder":"scan","current":100,"total":400}},` +
+			`{"id":6,"type":"FolderScanProgress","data":{"folder":"calm","current":50,"total":100}},` +
+			`{"id":7,"type":"FolderScanProgress","data":{"folder":"scan","current":300,"total":400}}]`,
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/events" {
+			sinceSeen = append(sinceSeen, r.URL.Query().Get("since"))
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, CollectScanProgress: true})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	progress := make(map[string]*float64)
+	for _, folder := range snapshot.Folders {
+		progress[folder.ID] = folder.ScanProgressPct
+	}
+	if progress["scan"] == nil || *progress["scan"] != 75 {
+		t.Fatalf("expected the latest scan progress of 75%% for scan, got %v", progress["scan"])
+	}
+	if progress["calm"] != nil {
+		t.Fatalf("expected no scan progress for an idle folder, got %v", *progress["calm"])
+	}
+
+	c.refresh(context.Background(), time.Now().UTC())
+	if len(sinceSeen) != 2 || sinceSeen[0] != "0" || sinceSeen[1] != "7" {
+		t.Fatalf("expected events to be read from the last seen ID, got since=%v", sinceSeen)
+	}
+}
+
+func TestCollectorClampsFolderCompletion(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		want    float64
+		missing bool
+	}{
+		{name: "slightly over", body: `{"completion":100.0000001,"needBytes":0,"needItems":0,"globalBytes":1000}`, want: 100},
+		{name: "slightly negative", body: `{"completion":-0.0001,"needBytes":1000,"needItems":1,"globalBytes":1000}`, want: 0},
+		{name: "in range", body: `{"completion":42.5,"needBytes":500,"needItems":1,"globalBytes":1000}`, want: 42.5},
+		{name: "missing", body: `{"needBytes":0,"needItems":0,"globalBytes":1000}`, missing: true},
+	}
+	for _, tc := range cases {
+		ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{"/rest/db/completion": tc.body}))
+
+		client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+		c := New(client, Options{PollInterval: 5 * time.Second})
+		c.refresh(context.Background(), time.Now().UTC())
+		ts.Close()
+
+		snapshot, _ := c.Snapshot()
+		got := snapshot.Folders[0].CompletionPct
+		if tc.missing {
+			if got != nil {
+				t.Fatalf("%s: expected no completion, got %v", tc.name, *got)
+			}
+			continue
+		}
+		if got == nil || *got != tc.want {
+			t.Fatalf("%s: expected completion %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestCollectorCountsSharingDevices(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[` +
+			`{"id":"shared","label":"Shared","path":"/a","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"},{"deviceID":"REMOTE-2"}]},` +
+			`{"id":"lonely","label":"Lonely","path":"/b","devices":[{"deviceID":"LOCAL-1"}]}]}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	sharedWith := make(map[string]int)
+	for _, folder := range snapshot.Folders {
+		sharedWith[folder.ID] = folder.SharedWith
+	}
+	if sharedWith["shared"] != 2 || sharedWith["lonely"] != 0 {
+		t.Fatalf("expected the local device not to be counted, got %v", sharedWith)
+	}
+
+	var notShared []string
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "FOLDER_NOT_SHARED" {
+			notShared = append(notShared, alert.SubjectID)
+		}
+	}
+	if len(notShared) != 1 || notShared[0] != "lonely" {
+		t.Fatalf("expected one FOLDER_NOT_SHARED alert for lonely, got %+v", snapshot.Alerts)
+	}
+}
+
+func TestCollectorDropsDuplicateFolderIDs(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[` +
+			`{"id":"photos","label":"Photos","path":"/a","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"}]},` +
+			`{"id":"docs","label":"Docs","path":"/b","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"}]},` +
+			`{"id":"photos","label":"Photos copy","path":"/c","devices":[{"deviceID":"LOCAL-1"}]}]}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	if len(snapshot.Folders) != 2 {
+		t.Fatalf("expected the duplicate folder to be dropped, got %+v", snapshot.Folders)
+	}
+	for _, folder := range snapshot.Folders {
+		if folder.ID == "photos" && (folder.Label != "Photos" || folder.Path != "/a") {
+			t.Fatalf("expected the first photos folder to be kept, got %+v", folder)
+		}
+	}
+
+	var duplicates []model.Alert
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "DUPLICATE_FOLDER_ID" {
+			duplicates = append(duplicates, alert)
+		}
+	}
+	if len(duplicates) != 1 || duplicates[0].SubjectID != "photos" || duplicates[0].Severity != "warn" {
+		t.Fatalf("expected one DUPLICATE_FOLDER_ID warn alert for photos, got %+v", snapshot.Alerts)
+	}
+}
+
+func TestCollectorSkipsFoldersRemovedMidPoll(t *testing.T) {
+	base := fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"laptop"}],"folders":[` +
+			`{"id":"photos","label":"Photos","path":"/a","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"}]},` +
+			`{"id":"gone","label":"Gone","path":"/b","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"}]}]}`,
+		"/rest/system/connections": `{"total":{},"connections":{"REMOTE-1":{"connected":true}}}`,
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/rest/db/") && r.URL.Query().Get("folder") == "gone" {
+			http.Error(w, "no such folder", http.StatusNotFound)
+			return
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, CollectRemoteCompletion: true})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, ok := c.Snapshot()
+	if !ok {
+		t.Fatalf("expected a snapshot despite the vanished folder")
+	}
+	if len(snapshot.Folders) != 1 || snapshot.Folders[0].ID != "photos" {
+		t.Fatalf("expected only the photos folder, got %+v", snapshot.Folders)
+	}
+	for _, alert := range snapshot.Alerts {
+		if alert.Severity == "critical" {
+			t.Fatalf("expected no critical alert for a vanished folder, got %+v", alert)
+		}
+	}
+	if len(snapshot.Remotes) != 1 || snapshot.Remotes[0].CompletionPct == nil || *snapshot.Remotes[0].CompletionPct != 100 {
+		t.Fatalf("expected the remote completion to ignore the vanished folder, got %+v", snapshot.Remotes)
+	}
+}
+
+func TestCollectorReportsIgnoreParseErrors(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[` +
+			`{"id":"broken","label":"Broken","path":"/a"},` +
+			`{"id":"clean","label":"Clean","path":"/b"}]}`,
+		"/rest/db/ignores?folder=broken": `{"ignore":["// build output","(?d).DS_Store","[unclosed"],"expanded":null,"error":"syntax error in pattern [unclosed"}`,
+		"/rest/db/ignores?folder=clean":  `{"ignore":["// nothing to see","","*.tmp","#include shared.stignore"],"expanded":["*.tmp"]}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, CollectIgnores: true})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	for _, folder := range snapshot.Folders {
+		switch folder.ID {
+		case "broken":
+			if folder.IgnorePatterns != 2 || folder.IgnoreError == nil || *folder.IgnoreError != "syntax error in pattern [unclosed" {
+				t.Fatalf("expected two patterns and the parse error, got %d %v", folder.IgnorePatterns, folder.IgnoreError)
+			}
+		case "clean":
+			if folder.IgnorePatterns != 2 || folder.IgnoreError != nil {
+				t.Fatalf("expected two patterns and no error, got %d %v", folder.IgnorePatterns, folder.IgnoreError)
+			}
+		}
+	}
+
+	var ignoreAlerts []model.Alert
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "IGNORE_PARSE_ERROR" {
+			ignoreAlerts = append(ignoreAlerts, alert)
+		}
+	}
+	if len(ignoreAlerts) != 1 || ignoreAlerts[0].SubjectID != "broken" || ignoreAlerts[0].Severity != "warn" {
+		t.Fatalf("expected one IGNORE_PARSE_ERROR warn alert for broken, got %+v", snapshot.Alerts)
+	}
+}
+
+func TestCollectorReportsFoldersWithOnlyPausedPeers(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"laptop","paused":true},{"deviceID":"REMOTE-2","name":"phone"}],"folders":[` +
+			`{"id":"solo","label":"Solo","path":"/a","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"}]},` +
+			`{"id":"mixed","label":"Mixed","path":"/b","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"},{"deviceID":"REMOTE-2"}]},` +
+			`{"id":"held","label":"Held","path":"/c","paused":true,"devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"}]}]}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	var peersPaused []model.Alert
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "FOLDER_PEERS_PAUSED" {
+			peersPaused = append(peersPaused, alert)
+		}
+	}
+	if len(peersPaused) != 1 || peersPaused[0].SubjectID != "solo" || peersPaused[0].Severity != "info" {
+		t.Fatalf("expected one FOLDER_PEERS_PAUSED info alert for solo, got %+v", snapshot.Alerts)
+	}
+	if !strings.Contains(peersPaused[0].Message, "laptop") {
+		t.Fatalf("expected the paused peer to be named, got %q", peersPaused[0].Message)
+	}
+	for _, folder := range snapshot.Folders {
+		if folder.ID == "solo" && folder.State == "paused" {
+			t.Fatalf("expected solo to keep its own state, got %q", folder.State)
+		}
+	}
+}
+
+func TestCollectorHandlesMissingLocalID(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/system/status":      `{"myID":"","uptime":120}`,
+		"/rest/system/connections": `{"total":{},"connections":{"REMOTE-1":{"connected":true,"address":"tcp://10.0.0.5:22000"}}}`,
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"laptop"}],"folders":[` +
+			`{"id":"app","label":"app","path":"/a","devices":[{"deviceID":"LOCAL-1"},{"deviceID":"REMOTE-1"}]}]}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	if !snapshot.SourceOnline {
+		t.Fatalf("expected a usable snapshot, got %+v", snapshot)
+	}
+	if len(snapshot.Remotes) != 1 || snapshot.Remotes[0].ID != "REMOTE-1" {
+		t.Fatalf("expected the local device to stay out of remotes, got %+v", snapshot.Remotes)
+	}
+	if snapshot.Device.ID != "LOCAL-1" || snapshot.Device.Name != "vault" {
+		t.Fatalf("expected the local device to be inferred from the config, got %+v", snapshot.Device)
+	}
+	if len(snapshot.Folders) != 1 || snapshot.Folders[0].SharedWith != 1 {
+		t.Fatalf("expected the folder to be shared with one remote, got %+v", snapshot.Folders)
+	}
+
+	var unknown []model.Alert
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "LOCAL_ID_UNKNOWN" {
+			unknown = append(unknown, alert)
+		}
+	}
+	if len(unknown) != 1 || unknown[0].Severity != "warn" {
+		t.Fatalf("expected one LOCAL_ID_UNKNOWN warn alert, got %+v", snapshot.Alerts)
+	}
+}
+
+func TestCollectorReportsDiscoveryDetails(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/system/status": `{"myID":"LOCAL-1","uptime":120,"discoveryStatus":{` +
+			`"global@https://discovery-v4.syncthing.net/v2/":{"error":null},` +
+			`"global@https://discovery-v6.syncthing.net/v2/":{"error":"lookup timeout"},` +
+			`"IPv4 local":{"error":""}}}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	device := snapshot.Device
+	if device.DiscoveryOK != 2 || device.DiscoveryTotal != 3 {
+		t.Fatalf("expected discovery counts to be kept, got %d/%d", device.DiscoveryOK, device.DiscoveryTotal)
+	}
+	lookupTimeout := "lookup timeout"
+	want := []model.DiscoveryMethodStatus{
+		{Name: "IPv4 local", OK: true},
+		{Name: "global@https://discovery-v4.syncthing.net/v2/", OK: true},
+		{Name: "global@https://discovery-v6.syncthing.net/v2/", Error: &lookupTimeout},
+	}
+	if !reflect.DeepEqual(device.DiscoveryDetails, want) {
+		t.Fatalf("unexpected discovery details: %+v", device.DiscoveryDetails)
+	}
+}
+
+func TestCollectorReportsDiscoveryErrorsWithoutStatus(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/system/status": `{"myID":"LOCAL-1","uptime":120,"discoveryMethods":4,"discoveryErrors":{"global@https://discovery.syncthing.net/v2/":"500 Internal Server Error"}}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	details := snapshot.Device.DiscoveryDetails
+	if snapshot.Device.DiscoveryOK != 3 || snapshot.Device.DiscoveryTotal != 4 {
+		t.Fatalf("unexpected discovery counts: %d/%d", snapshot.Device.DiscoveryOK, snapshot.Device.DiscoveryTotal)
+	}
+	if len(details) != 1 || details[0].OK || details[0].Error == nil || *details[0].Error != "500 Internal Server Error" {
+		t.Fatalf("expected only the failing method to be listed, got %+v", details)
+	}
+}
+
+func TestCollectorTracksConnectedPeak(t *testing.T) {
+	var connected atomic.Int32
+	base := fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"R1","name":"r1"},{"deviceID":"R2","name":"r2"},{"deviceID":"R3","name":"r3"},{"deviceID":"R4","name":"r4"}],"folders":[]}`,
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/system/connections" {
+			var entries []string
+			for i := 1; i <= 4; i++ {
+				entries = append(entries, fmt.Sprintf(`"R%d":{"connected":%t}`, i, int32(i) <= connected.Load()))
+			}
+			_, _ = w.Write([]byte(`{"total":{},"connections":{` + strings.Join(entries, ",") + `}}`))
+			return
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, ConnectivityWindow: time.Hour})
+	hasDegraded := func(snapshot model.DashboardSnapshot) bool {
+		for _, alert := range snapshot.Alerts {
+			if alert.Code == "CONNECTIVITY_DEGRADED" {
+				return true
+			}
+		}
+		return false
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	connected.Store(4)
+	c.refresh(context.Background(), now)
+	snapshot, _ := c.Snapshot()
+	if snapshot.Device.RemotesConnectedPeak != 4 || hasDegraded(snapshot) {
+		t.Fatalf("expected peak 4 without alert, got peak %d alerts %+v", snapshot.Device.RemotesConnectedPeak, snapshot.Alerts)
+	}
+
+	connected.Store(2)
+	c.refresh(context.Background(), now.Add(10*time.Minute))
+	snapshot, _ = c.Snapshot()
+	if snapshot.Device.RemotesConnectedPeak != 4 || hasDegraded(snapshot) {
+		t.Fatalf("expected half the peak not to alert, got peak %d alerts %+v", snapshot.Device.RemotesConnectedPeak, snapshot.Alerts)
+	}
+
+	connected.Store(1)
+	c.refresh(context.Background(), now.Add(20*time.Minute))
+	snapshot, _ = c.Snapshot()
+	if snapshot.Device.RemotesConnectedPeak != 4 || !hasDegraded(snapshot) {
+		t.Fatalf("expected CONNECTIVITY_DEGRADED below half the peak, got peak %d alerts %+v", snapshot.Device.RemotesConnectedPeak, snapshot.Alerts)
+	}
+
+	// Once the peak leaves the window, the 2 seen since takes over, then 1.
+	c.refresh(context.Background(), now.Add(65*time.Minute))
+	snapshot, _ = c.Snapshot()
+	if snapshot.Device.RemotesConnectedPeak != 2 || hasDegraded(snapshot) {
+		t.Fatalf("expected peak to decay to 2, got peak %d alerts %+v", snapshot.Device.RemotesConnectedPeak, snapshot.Alerts)
+	}
+	c.refresh(context.Background(), now.Add(75*time.Minute))
+	snapshot, _ = c.Snapshot()
+	if snapshot.Device.RemotesConnectedPeak != 1 {
+		t.Fatalf("expected peak to decay to the current count, got %d", snapshot.Device.RemotesConnectedPeak)
+	}
+}
+
+func TestCollectorReportsNeedBreakdown(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config":        `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[{"id":"app","label":"app","path":"/mnt/vault/app"}]}`,
+		"/rest/db/status":     `{"globalFiles":40,"localFiles":10,"globalBytes":4000,"localBytes":1000,"needFiles":6,"needDirectories":2,"needSymlinks":1,"needDeletes":21,"needBytes":3000,"needTotalItems":30,"state":"syncing"}`,
+		"/rest/db/completion": `{"completion":25,"needBytes":3000,"needItems":31,"globalBytes":4000}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	snapshot, _ := c.Snapshot()
+	if len(snapshot.Folders) != 1 {
+		t.Fatalf("expected one folder, got %+v", snapshot.Folders)
+	}
+	folder := snapshot.Folders[0]
+	if folder.NeedFiles != 6 || folder.NeedDirectories != 2 || folder.NeedSymlinks != 1 || folder.NeedDeletes != 21 {
+		t.Fatalf("unexpected need breakdown: %+v", folder)
+	}
+	// The total comes from db/completion, which may be one poll ahead.
+	sum := folder.NeedFiles + folder.NeedDirectories + folder.NeedSymlinks + folder.NeedDeletes
+	if diff := folder.NeedItems - sum; diff < -1 || diff > 1 {
+		t.Fatalf("expected breakdown %d to roughly sum to need items %d", sum, folder.NeedItems)
+	}
+}
+
+func TestCollectorSuppressesAlertSeveritiesButKeepsSourceAlert(t *testing.T) {
+	var failing atomic.Bool
+	base := fakeSyncthingHandler(t, map[string]string{
+		"/rest/config":        `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[{"id":"app","label":"app","path":"/mnt/vault/app","paused":false,"devices":[{"deviceID":"REMOTE-1"}]}]}`,
+		"/rest/db/status":     `{"globalFiles":2,"localFiles":1,"globalBytes":2000,"localBytes":1000,"needFiles":1,"needBytes":1000,"state":"syncing"}`,
+		"/rest/db/completion": `{"completion":50,"needBytes":1000,"needItems":1,"globalBytes":2000}`,
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, SuppressSeverities: []string{"warn", "critical"}})
+	now := time.Now().UTC()
+	c.refresh(context.Background(), now)
+
+	snapshot, _ := c.Snapshot()
+	if len(snapshot.Folders) != 1 || snapshot.Folders[0].State != "syncing" || snapshot.Folders[0].NeedBytes == 0 {
+		t.Fatalf("expected folder state to be unaffected by suppression, got %+v", snapshot.Folders)
+	}
+	if len(snapshot.Alerts) != 0 {
+		t.Fatalf("expected warn alerts to be suppressed, got %+v", snapshot.Alerts)
+	}
+
+	failing.Store(true)
+	c.refresh(context.Background(), now.Add(time.Second))
+
+	snapshot, _ = c.Snapshot()
+	if len(snapshot.Alerts) != 1 || snapshot.Alerts[0].Code != "SOURCE_UNREACHABLE" || snapshot.Alerts[0].Severity != "critical" {
+		t.Fatalf("expected the critical SOURCE_UNREACHABLE alert to survive suppression, got %+v", snapshot.Alerts)
+	}
+}
+
+func TestCollectorSkipsCompletionForPausedFolders(t *testing.T) {
+	var pausedCompletionCalls atomic.Int64
+	base := fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[` +
+			`{"id":"active","label":"active","path":"/a"},{"id":"held","label":"held","path":"/h","paused":true}]}`,
+		"/rest/db/status?folder=held": `{"globalBytes":1000,"localBytes":200,"needBytes":800,"needFiles":5,"state":"idle"}`,
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/db/completion" && r.URL.Query().Get("folder") == "held" {
+			pausedCompletionCalls.Add(1)
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Now().UTC())
+
+	if calls := pausedCompletionCalls.Load(); calls != 0 {
+		t.Fatalf("expected no completion call for the paused folder, got %d", calls)
+	}
+
+	snapshot, _ := c.Snapshot()
+	for _, folder := range snapshot.Folders {
+		if folder.ID != "held" {
+			continue
+		}
+		if folder.State != "paused" || folder.NeedItems != 0 || folder.NeedBytes != 0 || folder.CompletionPct != nil {
+			t.Fatalf("expected paused folder without need or completion, got %+v", folder)
+		}
+	}
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "FOLDER_OUT_OF_SYNC" {
+			t.Fatalf("expected no out-of-sync alert for a paused folder, got %+v", alert)
+		}
+	}
+}
+
+func TestCollectorRotatesFoldersUnderMaxFolders(t *testing.T) {
+	var mu sync.Mutex
+	statusCalls := make(map[string]int)
+	base := fakeSyncthingHandler(t, map[string]string{
+		"/rest/config": `{"devices":[{"deviceID":"LOCAL-1","name":"vault"}],"folders":[` +
+			`{"id":"a","label":"a","path":"/a"},{"id":"b","label":"b","path":"/b"},` +
+			`{"id":"c","label":"c","path":"/c"},{"id":"d","label":"d","path":"/d"}]}`,
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/db/status" {
+			mu.Lock()
+			statusCalls[r.URL.Query().Get("folder")]++
+			mu.Unlock()
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second, MaxFolders: 2})
+	now := time.Now().UTC()
+
+	c.refresh(context.Background(), now)
+	snapshot, _ := c.Snapshot()
+	if len(statusCalls) != 2 || len(snapshot.Folders) != 2 {
+		t.Fatalf("expected the first poll to collect two folders, got calls=%v folders=%d", statusCalls, len(snapshot.Folders))
+	}
+
+	c.refresh(context.Background(), now.Add(5*time.Second))
+	snapshot, _ = c.Snapshot()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		if statusCalls[id] != 1 {
+			t.Fatalf("expected two polls to fetch each folder once, got %v", statusCalls)
+		}
+	}
+	if len(snapshot.Folders) != 4 {
+		t.Fatalf("expected folders skipped this poll to keep their last values, got %d folders", len(snapshot.Folders))
+	}
+	if snapshot.Device.LocalBytesTotal != 4000 {
+		t.Fatalf("expected device totals to include retained folders, got %d", snapshot.Device.LocalBytesTotal)
+	}
+}
+
+func TestCollectorComputesSecondsAgo(t *testing.T) {
+	ts := httptest.NewServer(fakeSyncthingHandler(t, map[string]string{
+		"/rest/config":       `{"devices":[{"deviceID":"LOCAL-1","name":"vault"},{"deviceID":"REMOTE-1","name":"desk"},{"deviceID":"REMOTE-2","name":"new"}],"folders":[{"id":"app","label":"app","path":"/a"}]}`,
+		"/rest/stats/device": `{"REMOTE-1":{"lastSeen":"2026-02-05T19:58:30Z"}}`,
+		"/rest/stats/folder": `{"app":{"lastScan":"2026-02-05T19:30:00Z"}}`,
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+	c.refresh(context.Background(), time.Date(2026, 2, 5, 20, 0, 0, 0, time.UTC))
+
+	snapshot, _ := c.Snapshot()
+	for _, remote := range snapshot.Remotes {
+		switch remote.ID {
+		case "REMOTE-1":
+			if remote.LastSeenSecondsAgo == nil || *remote.LastSeenSecondsAgo != 90 {
+				t.Fatalf("expected desk to be last seen 90s ago, got %v", remote.LastSeenSecondsAgo)
+			}
+		case "REMOTE-2":
+			if remote.LastSeenSecondsAgo != nil {
+				t.Fatalf("expected a never-seen remote to have no seconds-ago")
+			}
+		}
+	}
+	if len(snapshot.Folders) != 1 || snapshot.Folders[0].LastScanSecondsAgo == nil || *snapshot.Folders[0].LastScanSecondsAgo != 1800 {
+		t.Fatalf("expected folder scanned 1800s ago, got %+v", snapshot.Folders)
+	}
+}
+
+func TestCollectorRefreshNowCoalesces(t *testing.T) {
+	var polls atomic.Int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	base := fakeSyncthingHandler(t, nil)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/system/status" {
+			polls.Add(1)
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+
+	errs := make(chan error, 1)
+	go func() { errs <- c.RefreshNow(context.Background()) }()
+	<-started
+
+	// A second caller waits on the running cycle; giving up early leaves it be.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.RefreshNow(ctx); err == nil {
+		t.Fatalf("expected the waiting caller to time out while the poll is held")
+	}
+	// A scheduled cycle due while one is running is dropped.
+	c.refresh(context.Background(), time.Now().UTC())
+	close(release)
+
+	if err := <-errs; err != nil {
+		t.Fatalf("RefreshNow returned error: %v", err)
+	}
+	if got := polls.Load(); got != 1 {
+		t.Fatalf("expected concurrent refreshes to share one poll, got %d", got)
+	}
+	if snapshot, ok := c.Snapshot(); !ok || !snapshot.SourceOnline {
+		t.Fatalf("expected RefreshNow to publish an online snapshot, got %+v", snapshot)
+	}
+
+	if err := c.RefreshNow(context.Background()); err != nil {
+		t.Fatalf("RefreshNow returned error: %v", err)
+	}
+	if got := polls.Load(); got != 2 {
+		t.Fatalf("expected a later refresh to poll again, got %d polls", got)
+	}
+}
+
+func TestCollectorRefreshNowOutlivesCaller(t *testing.T) {
+	release := make(chan struct{})
+	base := fakeSyncthingHandler(t, nil)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/system/status" {
+			<-release
+		}
+		base(w, r)
+	}))
+	defer ts.Close()
+
+	client := syncthing.NewClient(ts.URL, "key", 2*time.Second, false, syncthing.ClientOptions{})
+	c := New(client, Options{PollInterval: 5 * time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.RefreshNow(ctx); err == nil {
+		t.Fatalf("expected RefreshNow to return the caller's context error")
+	}
+	close(release)
+
+	if err := c.RefreshNow(context.Background()); err != nil {
+		t.Fatalf("RefreshNow returned error: %v", err)
+	}
+	if snapshot, ok := c.Snapshot(); !ok || !snapshot.SourceOnline {
+		t.Fatalf("expected the abandoned poll to still succeed, got %+v", snapshot)
+	}
+}
@@ -1,42 +1,162 @@
 package config
 
 import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+
+	"syncthing-dashboard/internal/model"
+	"syncthing-dashboard/internal/syncthing"
 )
 
 // Config stores runtime configuration for the dashboard service.
 type Config struct {
-	STBaseURL            string
-	STAPIKey             string
-	DemoMode             bool
-	PollInterval         time.Duration
-	HTTPListenAddr       string
-	HTTPReadTimeout      time.Duration
-	HTTPWriteTimeout     time.Duration
-	STTimeout            time.Duration
-	STInsecureSkipVerify bool
-	PageTitle            string
-	PageSubtitle         string
+	STBaseURL               string
+	STAPIKey                string
+	DemoMode                bool
+	DemoScenario            string
+	DemoFolders             *int
+	DemoRemotes             *int
+	PollInterval            time.Duration
+	PollTimeout             time.Duration
+	PollJitter              time.Duration
+	StaleAfter              time.Duration
+	HTTPListenAddr          string
+	HTTPAdminListenAddr     string
+	HTTPReadTimeout         time.Duration
+	HTTPWriteTimeout        time.Duration
+	LongPollTimeout         time.Duration
+	ShutdownTimeout         time.Duration
+	HTTPTLSCert             *tls.Certificate
+	STTimeout               time.Duration
+	STMaxIdleConns          int
+	STMaxResponseBytes      int64
+	STInsecureSkipVerify    bool
+	STTLSCertSHA256         string
+	STClientCert            *tls.Certificate
+	STExtraReadPaths        []string
+	PageTitle               string
+	PageSubtitle            string
+	InstanceName            string
+	CheckOnly               bool
+	CollectRemoteCompletion bool
+	CollectContributors     bool
+	CollectPending          bool
+	CollectScanProgress     bool
+	CollectIgnores          bool
+	FlapThreshold           int
+	FlapWindow              time.Duration
+	LowDiskFreeBytes        int64
+	LowDiskFreePct          float64
+	HistorySize             int
+	FolderSort              string
+	RemoteSort              string
+	MinVersion              string
+	Timezone                string
+	BigIntAsString          bool
+	EnablePprof             bool
+	SuppressSeverities      []string
+	RateLimit               float64
+	MaxConcurrentRequests   int
+	AccessLog               bool
+	AccessLogProbes         bool
+	MaxFolders              int
+	MaxAlerts               int
+	ConnectivityWindow      time.Duration
+	BasicUser               string
+	BasicPassword           string
+	FolderFilter            model.Filter
+	DeviceFilter            model.Filter
+	FolderGroups            model.FolderGroups
 }
 
-// Load reads environment variables and validates required settings.
+// Load reads environment variables, falling back to the settings file named by
+// SYNCTHING_DASHBOARD_CONFIG_FILE, and validates required settings.
 func Load() (Config, error) {
-	baseURL := strings.TrimSpace(os.Getenv("SYNCTHING_BASE_URL"))
+	settings, err := readConfigFile()
+	if err != nil {
+		return Config{}, err
+	}
+	// Settings come from the environment first and the settings file second;
+	// every parse helper reads through lookup.
+	lookup := func(name string) string {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+		return settings[name]
+	}
+
+	baseURL := strings.TrimSpace(lookup("SYNCTHING_BASE_URL"))
+
+	allowDemo, err := boolFromEnv(lookup, "SYNCTHING_DASHBOARD_ALLOW_DEMO", true)
+	if err != nil {
+		return Config{}, err
+	}
+	if baseURL == "" && !allowDemo {
+		return Config{}, fmt.Errorf("SYNCTHING_BASE_URL must be set when SYNCTHING_DASHBOARD_ALLOW_DEMO is false")
+	}
 
-	pollInterval, err := durationFromEnv("SYNCTHING_DASHBOARD_POLL_INTERVAL", 5*time.Second)
+	pollInterval, err := durationFromEnv(lookup, "SYNCTHING_DASHBOARD_POLL_INTERVAL", 5*time.Second)
 	if err != nil {
 		return Config{}, err
 	}
 	if pollInterval <= 0 {
 		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_POLL_INTERVAL must be > 0")
 	}
+	minPoll, err := durationFromEnv(lookup, "SYNCTHING_DASHBOARD_MIN_POLL", time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	maxPoll, err := durationFromEnv(lookup, "SYNCTHING_DASHBOARD_MAX_POLL", 10*time.Minute)
+	if err != nil {
+		return Config{}, err
+	}
+	if minPoll > maxPoll {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_MIN_POLL must not exceed SYNCTHING_DASHBOARD_MAX_POLL")
+	}
+	// Polling too fast gets rate-limited by the Syncthing GUI, too slow leaves
+	// the dashboard permanently stale.
+	if pollInterval < minPoll || pollInterval > maxPoll {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_POLL_INTERVAL must be between %s and %s (SYNCTHING_DASHBOARD_MIN_POLL and SYNCTHING_DASHBOARD_MAX_POLL)", minPoll, maxPoll)
+	}
+
+	pollTimeout, err := durationFromEnv(lookup, "SYNCTHING_DASHBOARD_POLL_TIMEOUT", pollInterval)
+	if err != nil {
+		return Config{}, err
+	}
+	if pollTimeout <= 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_POLL_TIMEOUT must be > 0")
+	}
+
+	pollJitter, err := durationFromEnv(lookup, "SYNCTHING_DASHBOARD_POLL_JITTER", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	if pollJitter < 0 || pollJitter >= pollInterval {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_POLL_JITTER must be >= 0 and shorter than the poll interval")
+	}
+
+	staleAfter, err := durationFromEnv(lookup, "SYNCTHING_DASHBOARD_STALE_AFTER", 2*pollInterval)
+	if err != nil {
+		return Config{}, err
+	}
+	if staleAfter <= 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_STALE_AFTER must be > 0")
+	}
 
-	httpReadTimeout, err := durationFromEnv("SYNCTHING_DASHBOARD_READ_TIMEOUT", 10*time.Second)
+	httpReadTimeout, err := durationFromEnv(lookup, "SYNCTHING_DASHBOARD_READ_TIMEOUT", 10*time.Second)
 	if err != nil {
 		return Config{}, err
 	}
@@ -44,7 +164,7 @@ func Load() (Config, error) {
 		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_READ_TIMEOUT must be > 0")
 	}
 
-	httpWriteTimeout, err := durationFromEnv("SYNCTHING_DASHBOARD_WRITE_TIMEOUT", 10*time.Second)
+	httpWriteTimeout, err := durationFromEnv(lookup, "SYNCTHING_DASHBOARD_WRITE_TIMEOUT", 10*time.Second)
 	if err != nil {
 		return Config{}, err
 	}
@@ -52,7 +172,23 @@ func Load() (Config, error) {
 		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_WRITE_TIMEOUT must be > 0")
 	}
 
-	stTimeout, err := durationFromEnv("SYNCTHING_TIMEOUT", 8*time.Second)
+	longPollTimeout, err := durationFromEnv(lookup, "SYNCTHING_DASHBOARD_LONG_POLL_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if longPollTimeout <= 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_LONG_POLL_TIMEOUT must be > 0")
+	}
+
+	shutdownTimeout, err := durationFromEnv(lookup, "SYNCTHING_DASHBOARD_SHUTDOWN_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	if shutdownTimeout <= 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_SHUTDOWN_TIMEOUT must be > 0")
+	}
+
+	stTimeout, err := durationFromEnv(lookup, "SYNCTHING_TIMEOUT", 8*time.Second)
 	if err != nil {
 		return Config{}, err
 	}
@@ -60,21 +196,328 @@ func Load() (Config, error) {
 		return Config{}, fmt.Errorf("SYNCTHING_TIMEOUT must be > 0")
 	}
 
-	stInsecureSkipVerify, err := boolFromEnv("SYNCTHING_INSECURE_SKIP_VERIFY", false)
+	stInsecureSkipVerify, err := boolFromEnv(lookup, "SYNCTHING_INSECURE_SKIP_VERIFY", false)
+	if err != nil {
+		return Config{}, err
+	}
+
+	checkOnly, err := boolFromEnv(lookup, "SYNCTHING_DASHBOARD_CHECK", false)
+	if err != nil {
+		return Config{}, err
+	}
+
+	collectRemoteCompletion, err := boolFromEnv(lookup, "SYNCTHING_DASHBOARD_COLLECT_REMOTE_COMPLETION", false)
+	if err != nil {
+		return Config{}, err
+	}
+
+	flapThreshold, err := intFromEnv(lookup, "SYNCTHING_DASHBOARD_FLAP_THRESHOLD", 6)
+	if err != nil {
+		return Config{}, err
+	}
+	if flapThreshold < 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_FLAP_THRESHOLD must be >= 0")
+	}
+
+	flapWindow, err := durationFromEnv(lookup, "SYNCTHING_DASHBOARD_FLAP_WINDOW", 15*time.Minute)
+	if err != nil {
+		return Config{}, err
+	}
+	if flapWindow <= 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_FLAP_WINDOW must be > 0")
+	}
+
+	connectivityWindow, err := durationFromEnv(lookup, "SYNCTHING_DASHBOARD_CONNECTIVITY_WINDOW", time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+	if connectivityWindow <= 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_CONNECTIVITY_WINDOW must be > 0")
+	}
+
+	lowDiskFreeBytes, err := intFromEnv(lookup, "SYNCTHING_DASHBOARD_LOW_DISK_FREE_BYTES", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	if lowDiskFreeBytes < 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_LOW_DISK_FREE_BYTES must be >= 0")
+	}
+
+	lowDiskFreePct, err := floatFromEnv(lookup, "SYNCTHING_DASHBOARD_LOW_DISK_FREE_PCT", 5)
+	if err != nil {
+		return Config{}, err
+	}
+	if lowDiskFreePct < 0 || lowDiskFreePct > 100 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_LOW_DISK_FREE_PCT must be between 0 and 100")
+	}
+
+	historySize, err := intFromEnv(lookup, "SYNCTHING_DASHBOARD_HISTORY_SIZE", 100)
+	if err != nil {
+		return Config{}, err
+	}
+	if historySize < 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_HISTORY_SIZE must be >= 0")
+	}
+
+	stMaxIdleConns, err := intFromEnv(lookup, "SYNCTHING_DASHBOARD_MAX_IDLE_CONNS", 4)
+	if err != nil {
+		return Config{}, err
+	}
+	if stMaxIdleConns <= 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_MAX_IDLE_CONNS must be > 0")
+	}
+
+	folderSort := strings.ToLower(stringFromEnv(lookup, "SYNCTHING_DASHBOARD_FOLDER_SORT", model.FolderSortLabel))
+	if !slices.Contains(model.FolderSortModes, folderSort) {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_FOLDER_SORT must be one of %s", strings.Join(model.FolderSortModes, ", "))
+	}
+
+	remoteSort := strings.ToLower(stringFromEnv(lookup, "SYNCTHING_DASHBOARD_REMOTE_SORT", model.RemoteSortName))
+	if !slices.Contains(model.RemoteSortModes, remoteSort) {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_REMOTE_SORT must be one of %s", strings.Join(model.RemoteSortModes, ", "))
+	}
+
+	minVersion := stringFromEnv(lookup, "SYNCTHING_DASHBOARD_MIN_VERSION", "")
+	if minVersion != "" {
+		if _, ok := model.ParseSemver(minVersion); !ok {
+			return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_MIN_VERSION must be a version like v2.0.0")
+		}
+	}
+
+	timezone := stringFromEnv(lookup, "SYNCTHING_DASHBOARD_TIMEZONE", "")
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_TIMEZONE: %w", err)
+		}
+	}
+
+	stMaxResponseBytes, err := intFromEnv(lookup, "SYNCTHING_DASHBOARD_MAX_RESPONSE_BYTES", 8<<20)
+	if err != nil {
+		return Config{}, err
+	}
+	if stMaxResponseBytes <= 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_MAX_RESPONSE_BYTES must be > 0")
+	}
+
+	folderInclude, err := patternsFromEnv(lookup, "SYNCTHING_DASHBOARD_FOLDER_INCLUDE")
+	if err != nil {
+		return Config{}, err
+	}
+	folderExclude, err := patternsFromEnv(lookup, "SYNCTHING_DASHBOARD_FOLDER_EXCLUDE")
+	if err != nil {
+		return Config{}, err
+	}
+
+	deviceInclude, err := patternsFromEnv(lookup, "SYNCTHING_DASHBOARD_DEVICE_INCLUDE")
+	if err != nil {
+		return Config{}, err
+	}
+	deviceExclude, err := patternsFromEnv(lookup, "SYNCTHING_DASHBOARD_DEVICE_EXCLUDE")
+	if err != nil {
+		return Config{}, err
+	}
+
+	stTLSCertSHA256 := syncthing.NormalizeFingerprint(lookup("SYNCTHING_TLS_CERT_SHA256"))
+	if stTLSCertSHA256 != "" {
+		if decoded, err := hex.DecodeString(stTLSCertSHA256); err != nil || len(decoded) != sha256.Size {
+			return Config{}, fmt.Errorf("SYNCTHING_TLS_CERT_SHA256 must be a hex SHA-256 fingerprint")
+		}
+	}
+
+	stClientCert, err := loadKeyPair(lookup, "SYNCTHING_CLIENT_CERT_FILE", "SYNCTHING_CLIENT_KEY_FILE")
+	if err != nil {
+		return Config{}, err
+	}
+
+	// Paths are matched exactly, so a query string would never match.
+	var stExtraReadPaths []string
+	for _, value := range strings.Split(lookup("SYNCTHING_DASHBOARD_EXTRA_READ_PATHS"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !strings.HasPrefix(value, "/rest/") || strings.ContainsAny(value, "?#") {
+			return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_EXTRA_READ_PATHS: %q must be a /rest/ path without a query", value)
+		}
+		stExtraReadPaths = append(stExtraReadPaths, value)
+	}
+
+	collectContributors, err := boolFromEnv(lookup, "SYNCTHING_DASHBOARD_COLLECT_CONTRIBUTORS", false)
+	if err != nil {
+		return Config{}, err
+	}
+
+	collectPending, err := boolFromEnv(lookup, "SYNCTHING_DASHBOARD_COLLECT_PENDING", false)
+	if err != nil {
+		return Config{}, err
+	}
+
+	collectScanProgress, err := boolFromEnv(lookup, "SYNCTHING_DASHBOARD_COLLECT_SCAN_PROGRESS", false)
+	if err != nil {
+		return Config{}, err
+	}
+
+	collectIgnores, err := boolFromEnv(lookup, "SYNCTHING_DASHBOARD_COLLECT_IGNORES", false)
+	if err != nil {
+		return Config{}, err
+	}
+
+	enablePprof, err := boolFromEnv(lookup, "SYNCTHING_DASHBOARD_ENABLE_PPROF", false)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var suppressSeverities []string
+	for _, value := range strings.Split(lookup("SYNCTHING_DASHBOARD_SUPPRESS_SEVERITIES"), ",") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if !slices.Contains(model.AlertSeverities, value) {
+			return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_SUPPRESS_SEVERITIES entries must be one of %s", strings.Join(model.AlertSeverities, ", "))
+		}
+		suppressSeverities = append(suppressSeverities, value)
+	}
+
+	rateLimit, err := floatFromEnv(lookup, "SYNCTHING_DASHBOARD_RATE_LIMIT", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	if rateLimit < 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_RATE_LIMIT must be >= 0")
+	}
+	maxConcurrentRequests, err := intFromEnv(lookup, "SYNCTHING_DASHBOARD_MAX_CONCURRENT_REQUESTS", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	if maxConcurrentRequests < 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_MAX_CONCURRENT_REQUESTS must be >= 0")
+	}
+
+	accessLog, err := boolFromEnv(lookup, "SYNCTHING_DASHBOARD_ACCESS_LOG", false)
+	if err != nil {
+		return Config{}, err
+	}
+	accessLogProbes, err := boolFromEnv(lookup, "SYNCTHING_DASHBOARD_ACCESS_LOG_PROBES", false)
+	if err != nil {
+		return Config{}, err
+	}
+	bigIntAsString, err := boolFromEnv(lookup, "SYNCTHING_DASHBOARD_BIGINT_AS_STRING", false)
+	if err != nil {
+		return Config{}, err
+	}
+
+	maxFolders, err := intFromEnv(lookup, "SYNCTHING_DASHBOARD_MAX_FOLDERS", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	if maxFolders < 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_MAX_FOLDERS must be >= 0")
+	}
+
+	maxAlerts, err := intFromEnv(lookup, "SYNCTHING_DASHBOARD_MAX_ALERTS", 100)
+	if err != nil {
+		return Config{}, err
+	}
+	if maxAlerts < 0 {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_MAX_ALERTS must be >= 0")
+	}
+
+	basicUser := strings.TrimSpace(lookup("SYNCTHING_DASHBOARD_BASIC_USER"))
+	basicPassword := lookup("SYNCTHING_DASHBOARD_BASIC_PASSWORD")
+	if (basicUser == "") != (basicPassword == "") {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_BASIC_USER and SYNCTHING_DASHBOARD_BASIC_PASSWORD must be set together")
+	}
+	if strings.Contains(basicUser, ":") {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_BASIC_USER must not contain a colon")
+	}
+
+	folderGroups, err := folderGroupsFromEnv(lookup, "SYNCTHING_DASHBOARD_FOLDER_GROUPS")
+	if err != nil {
+		return Config{}, err
+	}
+
+	httpTLSCert, err := loadKeyPair(lookup, "SYNCTHING_DASHBOARD_TLS_CERT", "SYNCTHING_DASHBOARD_TLS_KEY")
+	if err != nil {
+		return Config{}, err
+	}
+	if httpTLSCert != nil && strings.HasPrefix(stringFromEnv(lookup, "SYNCTHING_DASHBOARD_LISTEN_ADDRESS", ":8080"), "unix:") {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_TLS_CERT cannot be used with a unix socket listen address")
+	}
+	adminListenAddr := stringFromEnv(lookup, "SYNCTHING_DASHBOARD_ADMIN_LISTEN_ADDRESS", "")
+	if adminListenAddr != "" && adminListenAddr == stringFromEnv(lookup, "SYNCTHING_DASHBOARD_LISTEN_ADDRESS", ":8080") {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_ADMIN_LISTEN_ADDRESS must differ from SYNCTHING_DASHBOARD_LISTEN_ADDRESS")
+	}
+
+	demoScenario := strings.ToLower(stringFromEnv(lookup, "SYNCTHING_DASHBOARD_DEMO_SCENARIO", model.DemoScenarioMixed))
+	if !slices.Contains(model.DemoScenarios, demoScenario) {
+		return Config{}, fmt.Errorf("SYNCTHING_DASHBOARD_DEMO_SCENARIO must be one of %s", strings.Join(model.DemoScenarios, ", "))
+	}
+	demoFolders, err := demoCountFromEnv(lookup, "SYNCTHING_DASHBOARD_DEMO_FOLDERS")
+	if err != nil {
+		return Config{}, err
+	}
+	demoRemotes, err := demoCountFromEnv(lookup, "SYNCTHING_DASHBOARD_DEMO_REMOTES")
 	if err != nil {
 		return Config{}, err
 	}
 
 	cfg := Config{
-		DemoMode:             baseURL == "",
-		PollInterval:         pollInterval,
-		HTTPListenAddr:       stringFromEnv("SYNCTHING_DASHBOARD_LISTEN_ADDRESS", ":8080"),
-		HTTPReadTimeout:      httpReadTimeout,
-		HTTPWriteTimeout:     httpWriteTimeout,
-		STTimeout:            stTimeout,
-		STInsecureSkipVerify: stInsecureSkipVerify,
-		PageTitle:            stringFromEnv("SYNCTHING_DASHBOARD_TITLE", "Syncthing"),
-		PageSubtitle:         stringFromEnv("SYNCTHING_DASHBOARD_SUBTITLE", "Read-Only Dashboard"),
+		DemoMode:                baseURL == "",
+		DemoScenario:            demoScenario,
+		DemoFolders:             demoFolders,
+		DemoRemotes:             demoRemotes,
+		PollInterval:            pollInterval,
+		PollTimeout:             pollTimeout,
+		PollJitter:              pollJitter,
+		StaleAfter:              staleAfter,
+		HTTPListenAddr:          stringFromEnv(lookup, "SYNCTHING_DASHBOARD_LISTEN_ADDRESS", ":8080"),
+		HTTPAdminListenAddr:     adminListenAddr,
+		HTTPReadTimeout:         httpReadTimeout,
+		HTTPWriteTimeout:        httpWriteTimeout,
+		LongPollTimeout:         longPollTimeout,
+		ShutdownTimeout:         shutdownTimeout,
+		HTTPTLSCert:             httpTLSCert,
+		STTimeout:               stTimeout,
+		STMaxIdleConns:          stMaxIdleConns,
+		STMaxResponseBytes:      int64(stMaxResponseBytes),
+		STInsecureSkipVerify:    stInsecureSkipVerify,
+		STTLSCertSHA256:         stTLSCertSHA256,
+		STClientCert:            stClientCert,
+		STExtraReadPaths:        stExtraReadPaths,
+		PageTitle:               stringFromEnv(lookup, "SYNCTHING_DASHBOARD_TITLE", "Syncthing"),
+		PageSubtitle:            stringFromEnv(lookup, "SYNCTHING_DASHBOARD_SUBTITLE", "Read-Only Dashboard"),
+		InstanceName:            stringFromEnv(lookup, "SYNCTHING_DASHBOARD_INSTANCE_NAME", hostname()),
+		CheckOnly:               checkOnly,
+		CollectRemoteCompletion: collectRemoteCompletion,
+		CollectContributors:     collectContributors,
+		CollectPending:          collectPending,
+		CollectScanProgress:     collectScanProgress,
+		CollectIgnores:          collectIgnores,
+		FlapThreshold:           flapThreshold,
+		FlapWindow:              flapWindow,
+		LowDiskFreeBytes:        int64(lowDiskFreeBytes),
+		LowDiskFreePct:          lowDiskFreePct,
+		HistorySize:             historySize,
+		FolderSort:              folderSort,
+		RemoteSort:              remoteSort,
+		MinVersion:              minVersion,
+		Timezone:                timezone,
+		BigIntAsString:          bigIntAsString,
+		EnablePprof:             enablePprof,
+		SuppressSeverities:      suppressSeverities,
+		RateLimit:               rateLimit,
+		MaxConcurrentRequests:   maxConcurrentRequests,
+		AccessLog:               accessLog,
+		AccessLogProbes:         accessLogProbes,
+		MaxFolders:              maxFolders,
+		MaxAlerts:               maxAlerts,
+		ConnectivityWindow:      connectivityWindow,
+		BasicUser:               basicUser,
+		BasicPassword:           basicPassword,
+		FolderFilter:            model.Filter{Include: folderInclude, Exclude: folderExclude},
+		DeviceFilter:            model.Filter{Include: deviceInclude, Exclude: deviceExclude},
+		FolderGroups:            folderGroups,
 	}
 
 	if cfg.DemoMode {
@@ -85,8 +528,13 @@ func Load() (Config, error) {
 	if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
 		return Config{}, fmt.Errorf("SYNCTHING_BASE_URL must be a valid absolute URL")
 	}
+	// REST paths are appended to the base URL, so a path prefix works but a
+	// query or fragment would end up in front of them.
+	if parsedURL.RawQuery != "" || parsedURL.Fragment != "" {
+		return Config{}, fmt.Errorf("SYNCTHING_BASE_URL must not include a query or fragment")
+	}
 
-	apiKey, err := loadAPIKey()
+	apiKey, err := loadAPIKey(lookup)
 	if err != nil {
 		return Config{}, err
 	}
@@ -97,31 +545,70 @@ func Load() (Config, error) {
 	return cfg, nil
 }
 
-func loadAPIKey() (string, error) {
-	if apiKey := strings.TrimSpace(os.Getenv("SYNCTHING_API_KEY")); apiKey != "" {
+// secretsDir is where Docker and Podman mount secrets; a variable so tests can
+// point it elsewhere.
+var secretsDir = "/run/secrets"
+
+// loadAPIKey takes the API key from SYNCTHING_API_KEY, then the file named by
+// SYNCTHING_API_KEY_FILE, then the secret SYNCTHING_API_KEY_SECRET_NAME
+// (default syncthing_api_key) under secretsDir.
+func loadAPIKey(lookup func(string) string) (string, error) {
+	if apiKey := strings.TrimSpace(lookup("SYNCTHING_API_KEY")); apiKey != "" {
 		return apiKey, nil
 	}
 
-	secretPath := strings.TrimSpace(os.Getenv("SYNCTHING_API_KEY_FILE"))
-	if secretPath == "" {
-		return "", fmt.Errorf("either SYNCTHING_API_KEY or SYNCTHING_API_KEY_FILE must be set")
+	if secretPath := strings.TrimSpace(lookup("SYNCTHING_API_KEY_FILE")); secretPath != "" {
+		return readAPIKeyFile("SYNCTHING_API_KEY_FILE", secretPath)
+	}
+
+	secretName := stringFromEnv(lookup, "SYNCTHING_API_KEY_SECRET_NAME", "syncthing_api_key")
+	if secretName != filepath.Base(secretName) || secretName == "." || secretName == ".." {
+		return "", fmt.Errorf("SYNCTHING_API_KEY_SECRET_NAME must be a file name, not a path")
+	}
+	secretPath := filepath.Join(secretsDir, secretName)
+	if _, err := os.Stat(secretPath); errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("either SYNCTHING_API_KEY or SYNCTHING_API_KEY_FILE must be set, or a secret mounted at %s", secretPath)
 	}
+	return readAPIKeyFile(secretPath, secretPath)
+}
 
-	secretData, err := os.ReadFile(secretPath)
+// readAPIKeyFile reads a trimmed, non-empty API key from path. source names
+// where path came from in errors.
+func readAPIKeyFile(source, path string) (string, error) {
+	secretData, err := os.ReadFile(path)
 	if err != nil {
-		return "", fmt.Errorf("failed to read SYNCTHING_API_KEY_FILE: %w", err)
+		return "", fmt.Errorf("failed to read %s: %w", source, err)
 	}
 
 	apiKey := strings.TrimSpace(string(secretData))
 	if apiKey == "" {
-		return "", fmt.Errorf("SYNCTHING_API_KEY_FILE is empty")
+		return "", fmt.Errorf("%s is empty", source)
 	}
 
 	return apiKey, nil
 }
 
-func durationFromEnv(name string, fallback time.Duration) (time.Duration, error) {
-	value := strings.TrimSpace(os.Getenv(name))
+// loadKeyPair loads the PEM certificate and key named by the certEnv and
+// keyEnv variables, or returns nil when neither is set.
+func loadKeyPair(lookup func(string) string, certEnv, keyEnv string) (*tls.Certificate, error) {
+	certFile := strings.TrimSpace(lookup(certEnv))
+	keyFile := strings.TrimSpace(lookup(keyEnv))
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("%s and %s must be set together", certEnv, keyEnv)
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s and %s: %w", certEnv, keyEnv, err)
+	}
+	return &cert, nil
+}
+
+func durationFromEnv(lookup func(string) string, name string, fallback time.Duration) (time.Duration, error) {
+	value := strings.TrimSpace(lookup(name))
 	if value == "" {
 		return fallback, nil
 	}
@@ -136,11 +623,53 @@ func durationFromEnv(name string, fallback time.Duration) (time.Duration, error)
 		return time.Duration(seconds) * time.Second, nil
 	}
 
-	return 0, fmt.Errorf("%s: invalid duration %q", name, value)
+	if parsed, ok := parseLooseDuration(value); ok {
+		return parsed, nil
+	}
+
+	return 0, fmt.Errorf("%s: invalid duration %q (use e.g. 30s, 5m or 1h30m)", name, value)
+}
+
+var (
+	durationTermPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*([a-zµ]*)`)
+	durationUnitWords   = map[string]string{
+		"ns": "ns", "us": "us", "µs": "µs",
+		"ms": "ms", "msec": "ms", "msecs": "ms", "millisecond": "ms", "milliseconds": "ms",
+		"s": "s", "sec": "s", "secs": "s", "second": "s", "seconds": "s",
+		"m": "m", "min": "m", "mins": "m", "minute": "m", "minutes": "m",
+		"h": "h", "hr": "h", "hrs": "h", "hour": "h", "hours": "h",
+	}
+)
+
+// parseLooseDuration accepts durations written the way people type them:
+// spaces or commas between terms and unit words, e.g. "5 min", "300 s" or
+// "1 hour, 30 minutes".
+func parseLooseDuration(value string) (time.Duration, bool) {
+	value = strings.ToLower(value)
+	var normalized strings.Builder
+	last := 0
+	for _, match := range durationTermPattern.FindAllStringSubmatchIndex(value, -1) {
+		if strings.Trim(value[last:match[0]], " ,") != "" {
+			return 0, false
+		}
+		unit, ok := durationUnitWords[value[match[4]:match[5]]]
+		if !ok {
+			return 0, false
+		}
+		normalized.WriteString(value[match[2]:match[3]])
+		normalized.WriteString(unit)
+		last = match[1]
+	}
+	if last == 0 || strings.Trim(value[last:], " ,") != "" {
+		return 0, false
+	}
+
+	parsed, err := time.ParseDuration(normalized.String())
+	return parsed, err == nil
 }
 
-func boolFromEnv(name string, fallback bool) (bool, error) {
-	value := strings.TrimSpace(os.Getenv(name))
+func boolFromEnv(lookup func(string) string, name string, fallback bool) (bool, error) {
+	value := strings.TrimSpace(lookup(name))
 	if value == "" {
 		return fallback, nil
 	}
@@ -153,11 +682,109 @@ func boolFromEnv(name string, fallback bool) (bool, error) {
 	return parsed, nil
 }
 
-func stringFromEnv(name, fallback string) string {
-	value := strings.TrimSpace(os.Getenv(name))
+// demoCountFromEnv parses an optional demo entry count. Unset returns nil so
+// the scenario keeps its own entries.
+func demoCountFromEnv(lookup func(string) string, name string) (*int, error) {
+	if strings.TrimSpace(lookup(name)) == "" {
+		return nil, nil
+	}
+	count, err := intFromEnv(lookup, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	if count < 0 {
+		return nil, fmt.Errorf("%s must be >= 0", name)
+	}
+	return &count, nil
+}
+
+func intFromEnv(lookup func(string) string, name string, fallback int) (int, error) {
+	value := strings.TrimSpace(lookup(name))
+	if value == "" {
+		return fallback, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid integer %q", name, value)
+	}
+
+	return parsed, nil
+}
+
+func floatFromEnv(lookup func(string) string, name string, fallback float64) (float64, error) {
+	value := strings.TrimSpace(lookup(name))
+	if value == "" {
+		return fallback, nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid number %q", name, value)
+	}
+
+	return parsed, nil
+}
+
+// hostname is the OS hostname, or empty when it cannot be determined.
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+func stringFromEnv(lookup func(string) string, name, fallback string) string {
+	value := strings.TrimSpace(lookup(name))
 	if value == "" {
 		return fallback
 	}
 
 	return value
 }
+
+// patternsFromEnv splits a comma-separated list of glob patterns and rejects
+// malformed ones.
+func patternsFromEnv(lookup func(string) string, name string) ([]string, error) {
+	var patterns []string
+	for _, value := range strings.Split(lookup(name), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern %q", name, value)
+		}
+		patterns = append(patterns, value)
+	}
+	return patterns, nil
+}
+
+// folderGroupsFromEnv parses "group=folder-a,folder-b;other=folder-c" into a
+// folder ID to group mapping. A folder may belong to one group only.
+func folderGroupsFromEnv(lookup func(string) string, name string) (model.FolderGroups, error) {
+	groups := model.FolderGroups{}
+	for _, entry := range strings.Split(lookup(name), ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		group, folders, ok := strings.Cut(entry, "=")
+		group = strings.TrimSpace(group)
+		if !ok || group == "" {
+			return nil, fmt.Errorf("%s entries must look like group=folder-a,folder-b", name)
+		}
+		for _, folderID := range strings.Split(folders, ",") {
+			folderID = strings.TrimSpace(folderID)
+			if folderID == "" {
+				continue
+			}
+			if existing, dup := groups[folderID]; dup && existing != group {
+				return nil, fmt.Errorf("%s: folder %q is in both %q and %q", name, folderID, existing, group)
+			}
+			groups[folderID] = group
+		}
+	}
+	return groups, nil
+}
@@ -2,8 +2,13 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"syncthing-dashboard/internal/model"
 )
 
 func TestLoadEnablesDemoModeWhenBaseURLIsMissing(t *testing.T) {
@@ -27,7 +32,44 @@ func TestLoadEnablesDemoModeWhenBaseURLIsMissing(t *testing.T) {
 	}
 }
 
+func TestLoadAllowsDemoModeByDefault(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_ALLOW_DEMO", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !cfg.DemoMode {
+		t.Fatalf("expected DemoMode to be true")
+	}
+}
+
+func TestLoadRejectsMissingBaseURLWhenDemoDisallowed(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_ALLOW_DEMO", "false")
+
+	_, err := Load()
+	if err == nil {
+		t.Fatalf("expected error when demo mode is disallowed and SYNCTHING_BASE_URL is missing")
+	}
+	if !strings.Contains(err.Error(), "SYNCTHING_BASE_URL") {
+		t.Fatalf("expected error to mention SYNCTHING_BASE_URL, got %v", err)
+	}
+
+	t.Setenv("SYNCTHING_BASE_URL", "http://localhost:8384")
+	t.Setenv("SYNCTHING_API_KEY", "demo-key")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.DemoMode {
+		t.Fatalf("expected DemoMode to be false")
+	}
+}
+
 func TestLoadRequiresAPIKeyWhenBaseURLIsSet(t *testing.T) {
+	useSecretsDir(t, nil)
 	t.Setenv("SYNCTHING_BASE_URL", "http://localhost:8384")
 	t.Setenv("SYNCTHING_API_KEY", "")
 	t.Setenv("SYNCTHING_API_KEY_FILE", "")
@@ -58,6 +100,24 @@ func TestLoadReadsSyncthingConfigWhenProvided(t *testing.T) {
 	}
 }
 
+func TestLoadKeepsBaseURLPathPrefix(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "https://host/syncthing/")
+	t.Setenv("SYNCTHING_API_KEY", "demo-key")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.STBaseURL != "https://host/syncthing" {
+		t.Fatalf("unexpected STBaseURL: %q", cfg.STBaseURL)
+	}
+
+	t.Setenv("SYNCTHING_BASE_URL", "https://host/syncthing?x=1")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for base URL with a query")
+	}
+}
+
 func TestLoadAcceptsNumericPollIntervalInSeconds(t *testing.T) {
 	t.Setenv("SYNCTHING_BASE_URL", "")
 	t.Setenv("SYNCTHING_DASHBOARD_POLL_INTERVAL", "2")
@@ -72,6 +132,52 @@ func TestLoadAcceptsNumericPollIntervalInSeconds(t *testing.T) {
 	}
 }
 
+func TestLoadPollTimeoutDefaultsToPollInterval(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_POLL_INTERVAL", "7s")
+	t.Setenv("SYNCTHING_DASHBOARD_POLL_TIMEOUT", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.PollTimeout != 7*time.Second {
+		t.Fatalf("expected poll timeout to default to poll interval, got %s", cfg.PollTimeout)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_POLL_TIMEOUT", "3s")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.PollTimeout != 3*time.Second {
+		t.Fatalf("expected poll timeout of 3s, got %s", cfg.PollTimeout)
+	}
+}
+
+func TestLoadStaleAfterDefaultsToTwicePollInterval(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_POLL_INTERVAL", "4s")
+	t.Setenv("SYNCTHING_DASHBOARD_STALE_AFTER", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.StaleAfter != 8*time.Second {
+		t.Fatalf("expected stale-after of 8s, got %s", cfg.StaleAfter)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_STALE_AFTER", "30s")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.StaleAfter != 30*time.Second {
+		t.Fatalf("expected stale-after of 30s, got %s", cfg.StaleAfter)
+	}
+}
+
 func TestLoadRejectsInvalidDuration(t *testing.T) {
 	t.Setenv("SYNCTHING_BASE_URL", "")
 	t.Setenv("SYNCTHING_DASHBOARD_POLL_INTERVAL", "not-a-duration")
@@ -82,6 +188,151 @@ func TestLoadRejectsInvalidDuration(t *testing.T) {
 	}
 }
 
+func TestLoadAcceptsLooseDurations(t *testing.T) {
+	cases := map[string]time.Duration{
+		"5 min":              5 * time.Minute,
+		"300 s":              300 * time.Second,
+		"1h 30m":             90 * time.Minute,
+		"1 hour, 30 minutes": 90 * time.Minute,
+		"2 Seconds":          2 * time.Second,
+		"1.5 hours":          90 * time.Minute,
+		"750 ms":             750 * time.Millisecond,
+	}
+	for value, want := range cases {
+		t.Setenv("SYNCTHING_BASE_URL", "")
+		t.Setenv("SYNCTHING_DASHBOARD_FLAP_WINDOW", value)
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("%q: Load returned error: %v", value, err)
+		}
+		if cfg.FlapWindow != want {
+			t.Fatalf("%q: expected %s, got %s", value, want, cfg.FlapWindow)
+		}
+	}
+}
+
+func TestLoadNamesSettingWithInvalidDuration(t *testing.T) {
+	for _, value := range []string{"5 mins later", "5 fortnights", "m5", "5 min 3"} {
+		t.Setenv("SYNCTHING_BASE_URL", "")
+		t.Setenv("SYNCTHING_DASHBOARD_POLL_INTERVAL", value)
+
+		_, err := Load()
+		if err == nil {
+			t.Fatalf("%q: expected error instead of falling back to the default", value)
+		}
+		if !strings.Contains(err.Error(), "SYNCTHING_DASHBOARD_POLL_INTERVAL") {
+			t.Fatalf("%q: expected error to name the setting, got %v", value, err)
+		}
+	}
+}
+
+func TestLoadBasicAuthRequiresBothSettings(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_BASIC_USER", "admin")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for user without password")
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_BASIC_PASSWORD", "s3cret")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.BasicUser != "admin" || cfg.BasicPassword != "s3cret" {
+		t.Fatalf("unexpected basic auth settings: %q %q", cfg.BasicUser, cfg.BasicPassword)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_BASIC_USER", "ad:min")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for user containing a colon")
+	}
+}
+
+func TestLoadPollIntervalRange(t *testing.T) {
+	cases := []struct {
+		interval string
+		min, max string
+		ok       bool
+	}{
+		{interval: "100ms", ok: false},
+		{interval: "1s", ok: true},
+		{interval: "5m", ok: true},
+		{interval: "24h", ok: false},
+		{interval: "500ms", min: "250ms", ok: true},
+		{interval: "1h", max: "2h", ok: true},
+		{interval: "5s", min: "10s", ok: false},
+	}
+	for _, tc := range cases {
+		t.Setenv("SYNCTHING_BASE_URL", "")
+		t.Setenv("SYNCTHING_DASHBOARD_POLL_INTERVAL", tc.interval)
+		t.Setenv("SYNCTHING_DASHBOARD_MIN_POLL", tc.min)
+		t.Setenv("SYNCTHING_DASHBOARD_MAX_POLL", tc.max)
+
+		_, err := Load()
+		if tc.ok && err != nil {
+			t.Fatalf("%s (min %q, max %q): Load returned error: %v", tc.interval, tc.min, tc.max, err)
+		}
+		if !tc.ok && err == nil {
+			t.Fatalf("%s (min %q, max %q): expected out-of-range error", tc.interval, tc.min, tc.max)
+		}
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_POLL_INTERVAL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_MIN_POLL", "1m")
+	t.Setenv("SYNCTHING_DASHBOARD_MAX_POLL", "30s")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for a floor above the ceiling")
+	}
+}
+
+func TestLoadInstanceNameDefaultsToHostname(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_INSTANCE_NAME", "")
+
+	want, err := os.Hostname()
+	if err != nil {
+		t.Skipf("hostname unavailable: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.InstanceName != want {
+		t.Fatalf("expected instance name %q, got %q", want, cfg.InstanceName)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_INSTANCE_NAME", " nas-basement ")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.InstanceName != "nas-basement" {
+		t.Fatalf("expected the override, got %q", cfg.InstanceName)
+	}
+}
+
+func TestLoadParsesExtraReadPaths(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_EXTRA_READ_PATHS", " /rest/svc/report, ,/rest/noauth/health ")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	want := []string{"/rest/svc/report", "/rest/noauth/health"}
+	if !reflect.DeepEqual(cfg.STExtraReadPaths, want) {
+		t.Fatalf("expected %v, got %v", want, cfg.STExtraReadPaths)
+	}
+
+	for _, value := range []string{"/metrics", "rest/svc/report", "/rest/db/file?folder=a"} {
+		t.Setenv("SYNCTHING_DASHBOARD_EXTRA_READ_PATHS", value)
+		if _, err := Load(); err == nil {
+			t.Fatalf("%q: expected error", value)
+		}
+	}
+}
+
 func TestLoadRejectsInvalidBool(t *testing.T) {
 	t.Setenv("SYNCTHING_BASE_URL", "")
 	t.Setenv("SYNCTHING_INSECURE_SKIP_VERIFY", "yes-please")
@@ -163,3 +414,450 @@ func TestLoadAPIKeyFromEmptyFile(t *testing.T) {
 		t.Fatalf("expected error for empty API key file")
 	}
 }
+
+func useSecretsDir(t *testing.T, files map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write secret: %v", err)
+		}
+	}
+	previous := secretsDir
+	secretsDir = dir
+	t.Cleanup(func() { secretsDir = previous })
+}
+
+func TestLoadAPIKeyFallsBackToDefaultSecret(t *testing.T) {
+	useSecretsDir(t, map[string]string{
+		"syncthing_api_key": " secret-from-mount\n",
+		"custom_key":        "secret-from-custom",
+		"empty_key":         "",
+	})
+	t.Setenv("SYNCTHING_BASE_URL", "http://localhost:8384")
+	t.Setenv("SYNCTHING_API_KEY", "")
+	t.Setenv("SYNCTHING_API_KEY_FILE", "")
+	t.Setenv("SYNCTHING_API_KEY_SECRET_NAME", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.STAPIKey != "secret-from-mount" {
+		t.Fatalf("unexpected API key: %q", cfg.STAPIKey)
+	}
+
+	t.Setenv("SYNCTHING_API_KEY_SECRET_NAME", "custom_key")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.STAPIKey != "secret-from-custom" {
+		t.Fatalf("unexpected API key: %q", cfg.STAPIKey)
+	}
+
+	for _, name := range []string{"empty_key", "missing_key", "../syncthing_api_key"} {
+		t.Setenv("SYNCTHING_API_KEY_SECRET_NAME", name)
+		if _, err := Load(); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadAPIKeyPrecedence(t *testing.T) {
+	useSecretsDir(t, map[string]string{"syncthing_api_key": "secret-from-mount"})
+	keyFile := filepath.Join(t.TempDir(), "apikey")
+	if err := os.WriteFile(keyFile, []byte("secret-from-file"), 0o600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	t.Setenv("SYNCTHING_BASE_URL", "http://localhost:8384")
+	t.Setenv("SYNCTHING_API_KEY_SECRET_NAME", "")
+
+	t.Setenv("SYNCTHING_API_KEY", "secret-from-env")
+	t.Setenv("SYNCTHING_API_KEY_FILE", keyFile)
+	if cfg, err := Load(); err != nil || cfg.STAPIKey != "secret-from-env" {
+		t.Fatalf("expected SYNCTHING_API_KEY to win, got %q, %v", cfg.STAPIKey, err)
+	}
+
+	t.Setenv("SYNCTHING_API_KEY", "")
+	if cfg, err := Load(); err != nil || cfg.STAPIKey != "secret-from-file" {
+		t.Fatalf("expected SYNCTHING_API_KEY_FILE to win over the mounted secret, got %q, %v", cfg.STAPIKey, err)
+	}
+}
+
+func TestLoadReadsCheckOnlyFlag(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_CHECK", "1")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !cfg.CheckOnly {
+		t.Fatalf("expected CheckOnly to be true")
+	}
+}
+
+func TestLoadFlapDetectionSettings(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_FLAP_THRESHOLD", "3")
+	t.Setenv("SYNCTHING_DASHBOARD_FLAP_WINDOW", "5m")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.FlapThreshold != 3 || cfg.FlapWindow != 5*time.Minute {
+		t.Fatalf("unexpected flap settings: threshold=%d window=%s", cfg.FlapThreshold, cfg.FlapWindow)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_FLAP_THRESHOLD", "often")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for invalid flap threshold")
+	}
+}
+
+func TestLoadLowDiskThresholds(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_LOW_DISK_FREE_BYTES", "1073741824")
+	t.Setenv("SYNCTHING_DASHBOARD_LOW_DISK_FREE_PCT", "2.5")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.LowDiskFreeBytes != 1<<30 || cfg.LowDiskFreePct != 2.5 {
+		t.Fatalf("unexpected low disk thresholds: bytes=%d pct=%f", cfg.LowDiskFreeBytes, cfg.LowDiskFreePct)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_LOW_DISK_FREE_PCT", "150")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for out-of-range percentage")
+	}
+}
+
+func TestLoadHistorySize(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_HISTORY_SIZE", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.HistorySize != 100 {
+		t.Fatalf("expected default history size of 100, got %d", cfg.HistorySize)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_HISTORY_SIZE", "-1")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for negative history size")
+	}
+}
+
+func TestLoadMaxIdleConns(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_MAX_IDLE_CONNS", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.STMaxIdleConns != 4 {
+		t.Fatalf("expected default of 4 idle connections, got %d", cfg.STMaxIdleConns)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_MAX_IDLE_CONNS", "0")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for zero idle connections")
+	}
+}
+
+func TestLoadSortModes(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_FOLDER_SORT", "")
+	t.Setenv("SYNCTHING_DASHBOARD_REMOTE_SORT", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.FolderSort != "label" || cfg.RemoteSort != "name" {
+		t.Fatalf("unexpected default sort modes: %q, %q", cfg.FolderSort, cfg.RemoteSort)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_FOLDER_SORT", "Need_Bytes")
+	t.Setenv("SYNCTHING_DASHBOARD_REMOTE_SORT", "connection")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.FolderSort != "need_bytes" || cfg.RemoteSort != "connection" {
+		t.Fatalf("unexpected sort modes: %q, %q", cfg.FolderSort, cfg.RemoteSort)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_FOLDER_SORT", "size")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for unknown folder sort")
+	}
+}
+
+func TestLoadRejectsInvalidMinVersion(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_MIN_VERSION", "latest")
+
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for unparsable minimum version")
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_MIN_VERSION", "v2.0.0")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.MinVersion != "v2.0.0" {
+		t.Fatalf("unexpected minimum version %q", cfg.MinVersion)
+	}
+}
+
+func TestLoadShutdownTimeout(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_SHUTDOWN_TIMEOUT", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.ShutdownTimeout != 5*time.Second {
+		t.Fatalf("expected default shutdown timeout of 5s, got %s", cfg.ShutdownTimeout)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_SHUTDOWN_TIMEOUT", "0s")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for zero shutdown timeout")
+	}
+}
+
+func TestLoadValidatesTimezone(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_TIMEZONE", "America/Sao_Paulo")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Timezone != "America/Sao_Paulo" {
+		t.Fatalf("unexpected timezone %q", cfg.Timezone)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_TIMEZONE", "Mars/Olympus_Mons")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for unknown timezone")
+	}
+}
+
+func TestLoadMaxResponseBytes(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_MAX_RESPONSE_BYTES", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.STMaxResponseBytes != 8<<20 {
+		t.Fatalf("expected default of 8 MiB, got %d", cfg.STMaxResponseBytes)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_MAX_RESPONSE_BYTES", "0")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for zero response cap")
+	}
+}
+
+func TestLoadFolderFilter(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_FOLDER_INCLUDE", " photos, team-* ,")
+	t.Setenv("SYNCTHING_DASHBOARD_FOLDER_EXCLUDE", "team-secret")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.FolderFilter.Include, []string{"photos", "team-*"}) {
+		t.Fatalf("unexpected include patterns: %q", cfg.FolderFilter.Include)
+	}
+	if !reflect.DeepEqual(cfg.FolderFilter.Exclude, []string{"team-secret"}) {
+		t.Fatalf("unexpected exclude patterns: %q", cfg.FolderFilter.Exclude)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_FOLDER_EXCLUDE", "team-[")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for malformed pattern")
+	}
+}
+
+func TestLoadFolderGroups(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_FOLDER_GROUPS", "backups=folder-backups, folder-taxes; media=folder-media,folder-music;")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	want := model.FolderGroups{
+		"folder-backups": "backups",
+		"folder-taxes":   "backups",
+		"folder-media":   "media",
+		"folder-music":   "media",
+	}
+	if !reflect.DeepEqual(cfg.FolderGroups, want) {
+		t.Fatalf("unexpected folder groups: %v", cfg.FolderGroups)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_FOLDER_GROUPS", "folder-media")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for entry without a group name")
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_FOLDER_GROUPS", "backups=folder-media;media=folder-media")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for folder in two groups")
+	}
+}
+
+func TestLoadTLSCertFingerprint(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_TLS_CERT_SHA256", strings.Repeat("AB:", 31)+"AB")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.STTLSCertSHA256 != strings.Repeat("ab", 32) {
+		t.Fatalf("expected normalized fingerprint, got %q", cfg.STTLSCertSHA256)
+	}
+
+	t.Setenv("SYNCTHING_TLS_CERT_SHA256", "abcd")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for short fingerprint")
+	}
+}
+
+func TestLoadClientCertRequiresBothFiles(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+
+	t.Setenv("SYNCTHING_CLIENT_CERT_FILE", certFile)
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "must be set together") {
+		t.Fatalf("expected error for missing key file setting, got %v", err)
+	}
+
+	t.Setenv("SYNCTHING_CLIENT_KEY_FILE", keyFile)
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "failed to load SYNCTHING_CLIENT_CERT_FILE") {
+		t.Fatalf("expected error for unreadable key pair, got %v", err)
+	}
+
+	if err := os.WriteFile(certFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for malformed key pair")
+	}
+}
+
+func TestLoadPollJitter(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_POLL_INTERVAL", "5s")
+	t.Setenv("SYNCTHING_DASHBOARD_POLL_JITTER", "500ms")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.PollJitter != 500*time.Millisecond {
+		t.Fatalf("expected 500ms jitter, got %s", cfg.PollJitter)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_POLL_JITTER", "5s")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for jitter as long as the poll interval")
+	}
+}
+
+func TestLoadParsesDemoScenario(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_DEMO_SCENARIO", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.DemoScenario != "mixed" {
+		t.Fatalf("expected default demo scenario mixed, got %q", cfg.DemoScenario)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_DEMO_SCENARIO", "Degraded")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.DemoScenario != "degraded" {
+		t.Fatalf("expected demo scenario degraded, got %q", cfg.DemoScenario)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_DEMO_SCENARIO", "chaos")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for unknown demo scenario")
+	}
+}
+
+func TestLoadParsesDemoCounts(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_DEMO_FOLDERS", "")
+	t.Setenv("SYNCTHING_DASHBOARD_DEMO_REMOTES", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.DemoFolders != nil || cfg.DemoRemotes != nil {
+		t.Fatalf("expected unset demo counts by default, got %v and %v", cfg.DemoFolders, cfg.DemoRemotes)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_DEMO_FOLDERS", "0")
+	t.Setenv("SYNCTHING_DASHBOARD_DEMO_REMOTES", "25")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.DemoFolders == nil || *cfg.DemoFolders != 0 || cfg.DemoRemotes == nil || *cfg.DemoRemotes != 25 {
+		t.Fatalf("expected demo counts 0 and 25, got %v and %v", cfg.DemoFolders, cfg.DemoRemotes)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_DEMO_FOLDERS", "-1")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for a negative demo folder count")
+	}
+}
+
+func TestLoadParsesSuppressSeverities(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_DASHBOARD_SUPPRESS_SEVERITIES", " Warn, info ,")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(cfg.SuppressSeverities) != 2 || cfg.SuppressSeverities[0] != "warn" || cfg.SuppressSeverities[1] != "info" {
+		t.Fatalf("unexpected suppressed severities: %v", cfg.SuppressSeverities)
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_SUPPRESS_SEVERITIES", "warning")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for unknown severity")
+	}
+}
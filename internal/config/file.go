@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// configFileEnv names the optional settings file. It is only read from the
+// environment, never from the file itself.
+const configFileEnv = "SYNCTHING_DASHBOARD_CONFIG_FILE"
+
+// readConfigFile loads the settings file named by SYNCTHING_DASHBOARD_CONFIG_FILE.
+// Its keys are the environment variable names. JSON files hold one object of
+// scalars; YAML files are limited to flat "KEY: value" lines, which is all the
+// settings need. No file set returns nil.
+func readConfigFile() (map[string]string, error) {
+	path := strings.TrimSpace(os.Getenv(configFileEnv))
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", configFileEnv, err)
+	}
+
+	var settings map[string]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		settings, err = parseJSONSettings(data)
+	case ".yaml", ".yml":
+		settings, err = parseYAMLSettings(data)
+	default:
+		return nil, fmt.Errorf("%s must end in .json, .yaml or .yml", configFileEnv)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", configFileEnv, path, err)
+	}
+
+	for key := range settings {
+		if !strings.HasPrefix(key, "SYNCTHING_") || key == configFileEnv {
+			return nil, fmt.Errorf("%s %s: unknown setting %q", configFileEnv, path, key)
+		}
+	}
+	return settings, nil
+}
+
+func parseJSONSettings(data []byte) (map[string]string, error) {
+	var raw map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	settings := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			settings[key] = v
+		case json.Number:
+			settings[key] = v.String()
+		case bool:
+			settings[key] = strconv.FormatBool(v)
+		case nil:
+		default:
+			return nil, fmt.Errorf("%s must be a string, number or boolean", key)
+		}
+	}
+	return settings, nil
+}
+
+func parseYAMLSettings(data []byte) (map[string]string, error) {
+	settings := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		if text != trimmed {
+			return nil, fmt.Errorf("line %d: nested values are not supported", line)
+		}
+
+		key, value, ok := strings.Cut(text, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("line %d: expected KEY: value", line)
+		}
+		value, err := yamlScalar(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		settings[strings.TrimSpace(key)] = value
+	}
+	return settings, scanner.Err()
+}
+
+// yamlScalar unquotes a single- or double-quoted value and strips a trailing
+// comment from a plain one.
+func yamlScalar(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted value %s", value)
+		}
+		return unquoted, nil
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return "", fmt.Errorf("invalid quoted value %s", value)
+		}
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), nil
+	case strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") || strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+		return "", fmt.Errorf("only single-line scalar values are supported")
+	}
+	if before, _, found := strings.Cut(value, " #"); found {
+		value = strings.TrimSpace(before)
+	}
+	return value, nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadReadsConfigFile(t *testing.T) {
+	files := map[string]string{
+		"dashboard.json": `{"SYNCTHING_BASE_URL": "http://nas:8384", "SYNCTHING_API_KEY": "file-key", "SYNCTHING_DASHBOARD_POLL_INTERVAL": "7s", "SYNCTHING_DASHBOARD_MAX_ALERTS": 12, "SYNCTHING_DASHBOARD_COLLECT_PENDING": true}`,
+		"dashboard.yaml": "# dashboard settings\n" +
+			"SYNCTHING_BASE_URL: http://nas:8384\n" +
+			"SYNCTHING_API_KEY: \"file-key\"\n" +
+			"SYNCTHING_DASHBOARD_POLL_INTERVAL: '7s'  \n" +
+			"SYNCTHING_DASHBOARD_MAX_ALERTS: 12 # keep it short\n" +
+			"SYNCTHING_DASHBOARD_COLLECT_PENDING: true\n",
+	}
+	for name, content := range files {
+		t.Setenv("SYNCTHING_BASE_URL", "")
+		t.Setenv("SYNCTHING_API_KEY", "")
+		t.Setenv("SYNCTHING_DASHBOARD_POLL_INTERVAL", "")
+		t.Setenv("SYNCTHING_DASHBOARD_MAX_ALERTS", "")
+		t.Setenv("SYNCTHING_DASHBOARD_COLLECT_PENDING", "")
+		t.Setenv("SYNCTHING_DASHBOARD_CONFIG_FILE", writeConfigFile(t, name, content))
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("%s: Load returned error: %v", name, err)
+		}
+		if cfg.DemoMode || cfg.STBaseURL != "http://nas:8384" || cfg.STAPIKey != "file-key" {
+			t.Fatalf("%s: expected Syncthing settings from the file, got %+v", name, cfg)
+		}
+		if cfg.PollInterval != 7*time.Second || cfg.MaxAlerts != 12 || !cfg.CollectPending {
+			t.Fatalf("%s: expected dashboard settings from the file, got %+v", name, cfg)
+		}
+	}
+}
+
+func TestLoadEnvOverridesConfigFile(t *testing.T) {
+	t.Setenv("SYNCTHING_BASE_URL", "")
+	t.Setenv("SYNCTHING_API_KEY", "env-key")
+	t.Setenv("SYNCTHING_DASHBOARD_POLL_INTERVAL", "3s")
+	t.Setenv("SYNCTHING_DASHBOARD_CONFIG_FILE", writeConfigFile(t, "dashboard.yml",
+		"SYNCTHING_BASE_URL: http://nas:8384\nSYNCTHING_API_KEY: file-key\nSYNCTHING_DASHBOARD_POLL_INTERVAL: 7s\n"))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.STBaseURL != "http://nas:8384" {
+		t.Fatalf("expected the file to fill unset settings, got %q", cfg.STBaseURL)
+	}
+	if cfg.STAPIKey != "env-key" || cfg.PollInterval != 3*time.Second {
+		t.Fatalf("expected environment values to win, got key %q interval %s", cfg.STAPIKey, cfg.PollInterval)
+	}
+
+	// File values do not leak into a later Load without the file.
+	t.Setenv("SYNCTHING_DASHBOARD_CONFIG_FILE", "")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !cfg.DemoMode {
+		t.Fatalf("expected demo mode once the file is unset")
+	}
+}
+
+func TestLoadRejectsBadConfigFile(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "broken.json", content: `{"SYNCTHING_BASE_URL": `, want: "invalid JSON"},
+		{name: "list.json", content: `{"SYNCTHING_DASHBOARD_INCLUDE_FOLDERS": ["a"]}`, want: "must be a string"},
+		{name: "nested.yaml", content: "SYNCTHING_BASE_URL:\n  host: nas\n", want: "line 2"},
+		{name: "noise.yaml", content: "just some text\n", want: "line 1"},
+		{name: "typo.yaml", content: "POLL_INTERVAL: 5s\n", want: "unknown setting"},
+		{name: "settings.toml", content: "", want: ".json, .yaml or .yml"},
+		{name: "invalid.json", content: `{"SYNCTHING_DASHBOARD_POLL_INTERVAL": "0s"}`, want: "must be > 0"},
+		{name: "badurl.yaml", content: "SYNCTHING_BASE_URL: nas:8384\nSYNCTHING_API_KEY: k\n", want: "SYNCTHING_BASE_URL"},
+	}
+	for _, tc := range cases {
+		t.Setenv("SYNCTHING_BASE_URL", "")
+		t.Setenv("SYNCTHING_API_KEY", "")
+		t.Setenv("SYNCTHING_DASHBOARD_POLL_INTERVAL", "")
+		t.Setenv("SYNCTHING_DASHBOARD_CONFIG_FILE", writeConfigFile(t, tc.name, tc.content))
+
+		_, err := Load()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	t.Setenv("SYNCTHING_DASHBOARD_CONFIG_FILE", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for a missing config file")
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"crypto/tls"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// redactedMark replaces secret values that are set.
+const redactedMark = "***"
+
+// secretFields are the Config fields whose values are never shown.
+var secretFields = map[string]bool{
+	"STAPIKey":      true,
+	"BasicPassword": true,
+}
+
+// Redacted returns the configuration keyed by field name, safe to show to
+// anyone who can reach the dashboard. Secrets become "***" when set, the
+// Syncthing URL keeps only its scheme and host, certificates only say whether
+// one is loaded, and durations are written as Go durations like "5s".
+func (c Config) Redacted() map[string]any {
+	out := make(map[string]any)
+	value := reflect.ValueOf(c)
+	for i := range value.NumField() {
+		name := value.Type().Field(i).Name
+		field := value.Field(i)
+		switch v := field.Interface().(type) {
+		case string:
+			switch {
+			case secretFields[name] && v != "":
+				out[name] = redactedMark
+			case name == "STBaseURL":
+				out[name] = redactURL(v)
+			default:
+				out[name] = v
+			}
+		case *tls.Certificate:
+			out[name] = v != nil
+		case time.Duration:
+			out[name] = v.String()
+		default:
+			if field.Kind() == reflect.Pointer && !field.IsNil() {
+				out[name] = field.Elem().Interface()
+			} else {
+				out[name] = v
+			}
+		}
+	}
+	return out
+}
+
+// redactURL keeps the scheme and host of raw, dropping credentials, path,
+// query and fragment.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return redactedMark
+	}
+	return (&url.URL{Scheme: parsed.Scheme, Host: parsed.Host}).String()
+}
@@ -2,40 +2,138 @@ package demo
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"sync"
 	"time"
 
+	"syncthing-dashboard/internal/history"
 	"syncthing-dashboard/internal/model"
+	"syncthing-dashboard/internal/notify"
 )
 
 const (
 	kib = 1024
 	mib = 1024 * kib
 	gib = 1024 * mib
+	tib = 1024 * gib
 )
 
+// Options tunes the demo collector. Zero values fall back to defaults.
+type Options struct {
+	PollInterval time.Duration
+	// StaleAfter is the snapshot age after which it is reported as stale.
+	// Defaults to twice PollInterval.
+	StaleAfter time.Duration
+	// DiskSpace sets when LOW_DISK_SPACE is raised for a folder.
+	DiskSpace model.DiskSpaceThresholds
+	// HistorySize is how many snapshot summaries are retained. Zero disables history.
+	HistorySize int
+	// FolderSort and RemoteSort pick the model sort modes. Empty values sort
+	// folders by label and remotes by name.
+	FolderSort string
+	RemoteSort string
+	// MinVersion raises VERSION_OUTDATED when Syncthing is older. Empty disables the check.
+	MinVersion string
+	// FolderFilter limits which folders, by ID, are collected and shown.
+	FolderFilter model.Filter
+	// DeviceFilter limits which remote devices, by ID or name, are shown.
+	DeviceFilter model.Filter
+	// FolderGroups tags folders, by ID, with a display group.
+	FolderGroups model.FolderGroups
+	// SuppressSeverities drops alerts with these severities from snapshots.
+	SuppressSeverities []string
+	// MaxAlerts caps the alerts in a snapshot, most severe first, with an
+	// ALERTS_TRUNCATED summary for the rest. Zero keeps every alert.
+	MaxAlerts int
+	// Scenario picks the synthetic data set. Empty or unknown values use
+	// model.DemoScenarioMixed.
+	Scenario string
+	// Folders and Remotes, when set, generate exactly that many entries by
+	// cycling through the scenario's seeds. Nil keeps the scenario's own set.
+	Folders *int
+	Remotes *int
+}
+
 // Collector produces rich synthetic snapshots for demonstration mode.
 type Collector struct {
 	pollInterval time.Duration
+	staleAfter   time.Duration
+	diskSpace    model.DiskSpaceThresholds
+	history      *history.Ring
+	folderSort   string
+	remoteSort   string
+	minVersion   string
+	folderFilter model.Filter
+	deviceFilter model.Filter
+	folderGroups model.FolderGroups
+	scenario     string
+	folderCount  int
+	remoteCount  int
+	suppress     []string
+	maxAlerts    int
 
 	mu       sync.RWMutex
 	snapshot model.DashboardSnapshot
 	ready    bool
 	tick     int
 	startAt  time.Time
+
+	updates notify.Broadcaster
 }
 
-func NewCollector(pollInterval time.Duration) *Collector {
+func NewCollector(opts Options) *Collector {
+	pollInterval := opts.PollInterval
 	if pollInterval <= 0 {
 		pollInterval = 5 * time.Second
 	}
+	staleAfter := opts.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = 2 * pollInterval
+	}
+
+	scenario := opts.Scenario
+	if !slices.Contains(model.DemoScenarios, scenario) {
+		scenario = model.DemoScenarioMixed
+	}
 
 	return &Collector{
 		pollInterval: pollInterval,
+		staleAfter:   staleAfter,
+		diskSpace:    opts.DiskSpace,
+		history:      history.NewRing(opts.HistorySize),
+		folderSort:   opts.FolderSort,
+		remoteSort:   opts.RemoteSort,
+		minVersion:   opts.MinVersion,
+		folderFilter: opts.FolderFilter,
+		deviceFilter: opts.DeviceFilter,
+		folderGroups: opts.FolderGroups,
+		scenario:     scenario,
+		folderCount:  countOption(opts.Folders),
+		remoteCount:  countOption(opts.Remotes),
+		suppress:     opts.SuppressSeverities,
+		maxAlerts:    opts.MaxAlerts,
 		startAt:      time.Now().UTC().Add(-73 * time.Hour),
 	}
 }
 
+// countOption maps an unset or negative entry count to -1, which keeps the
+// scenario's own seeds.
+func countOption(count *int) int {
+	if count == nil || *count < 0 {
+		return -1
+	}
+	return *count
+}
+
+// demoModeAlert marks every demo snapshot as synthetic.
+var demoModeAlert = model.Alert{
+	Severity:  "info",
+	Code:      "DEMO_MODE",
+	Message:   "Showing synthetic demonstration data",
+	SubjectID: "demo",
+}
+
 func (c *Collector) Start(ctx context.Context) {
 	c.refresh()
 
@@ -67,22 +165,53 @@ func (c *Collector) Snapshot() (model.DashboardSnapshot, bool) {
 	}
 
 	out := c.snapshot
-	if time.Since(out.GeneratedAt) > 2*c.pollInterval {
+	if time.Since(out.GeneratedAt) > c.staleAfter {
 		out.Stale = true
 	}
 	return out, true
 }
 
+// Updated returns a channel that is closed when the next snapshot is built.
+func (c *Collector) Updated() <-chan struct{} {
+	return c.updates.Wait()
+}
+
+// History returns up to limit recent snapshot summaries, newest first.
+func (c *Collector) History(limit int) []history.Entry {
+	return c.history.Latest(limit)
+}
+
+// RefreshNow builds a new synthetic snapshot immediately.
+func (c *Collector) RefreshNow(context.Context) error {
+	c.refresh()
+	return nil
+}
+
 func (c *Collector) refresh() {
 	now := time.Now().UTC()
 
+	// Deferred first so waiters wake after the unlock below.
+	defer c.updates.Publish()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.snapshot = buildSnapshot(now, c.tick, c.startAt, c.pollInterval)
+	c.snapshot = buildSnapshot(now, c.tick, c.startAt, c.pollInterval, c.scenario, c.folderCount, c.remoteCount, c.diskSpace, c.folderFilter, c.deviceFilter)
+	c.folderGroups.Apply(c.snapshot.Folders)
+	model.SortFolders(c.snapshot.Folders, c.folderSort)
+	model.SortRemotes(c.snapshot.Remotes, c.remoteSort)
+	c.snapshot.Alerts = append(c.snapshot.Alerts, model.VersionAlerts(c.snapshot.Device.Version, c.minVersion, c.snapshot.Device.ID)...)
+	c.snapshot.Alerts = model.TruncateAlerts(model.SuppressAlerts(c.snapshot.Alerts, c.suppress), c.maxAlerts)
+	// The demo notice goes in after suppression and truncation so it is
+	// always shown, and first, so screenshots carry it too.
+	c.snapshot.Alerts = append([]model.Alert{demoModeAlert}, c.snapshot.Alerts...)
+	c.snapshot.Demo = true
+	c.snapshot.UpdateHealth()
+	c.snapshot.SnapshotID = c.snapshot.MeaningfulID()
 	c.snapshot.GeneratedAt = now
+	c.snapshot.CollectDurationMS = int64(38 + (c.tick*7)%45)
 	c.ready = true
 	c.tick++
+	c.history.Add(history.FromSnapshot(c.snapshot))
 }
 
 type folderSeed struct {
@@ -97,13 +226,25 @@ type folderSeed struct {
 	LocalChanges int64
 }
 
-func buildSnapshot(now time.Time, tick int, startAt time.Time, pollInterval time.Duration) model.DashboardSnapshot {
-	folders := buildFolders(now, tick)
-	remotes := buildRemotes(now, tick)
-	device := buildDevice(now, tick, startAt, pollInterval, folders)
+func buildSnapshot(now time.Time, tick int, startAt time.Time, pollInterval time.Duration, scenario string, folderCount, remoteCount int, diskSpace model.DiskSpaceThresholds, folderFilter, deviceFilter model.Filter) model.DashboardSnapshot {
+	folders := slices.DeleteFunc(buildFolders(now, tick, scenario, folderCount), func(folder model.FolderStatus) bool {
+		return !folderFilter.Allows(folder.ID)
+	})
+	remotes := slices.DeleteFunc(buildRemotes(now, tick, scenario, remoteCount), func(remote model.RemoteDeviceStatus) bool {
+		return !deviceFilter.Allows(remote.ID, remote.Name)
+	})
+	device := buildDevice(now, tick, startAt, pollInterval, folders, remotes)
 	alerts := model.DeriveAlerts(remotes, folders)
+	alerts = append(alerts, model.DiskSpaceAlerts(folders, diskSpace)...)
+	connected := 0
+	for _, remote := range remotes {
+		if remote.Connected {
+			connected++
+		}
+	}
+	alerts = append(alerts, model.ConnectivityAlerts(connected, device.RemotesConnectedPeak, device.ID)...)
 
-	return model.DashboardSnapshot{
+	snapshot := model.DashboardSnapshot{
 		GeneratedAt:  now,
 		SourceOnline: true,
 		SourceError:  nil,
@@ -113,20 +254,45 @@ func buildSnapshot(now time.Time, tick int, startAt time.Time, pollInterval time
 		Alerts:       alerts,
 		Stale:        false,
 	}
+	snapshot.PopulateDisplay()
+	snapshot.PopulateAges(now)
+	return snapshot
 }
 
-func buildDevice(now time.Time, tick int, startAt time.Time, pollInterval time.Duration, folders []model.FolderStatus) model.DeviceStatus {
+func buildDevice(now time.Time, tick int, startAt time.Time, pollInterval time.Duration, folders []model.FolderStatus, remotes []model.RemoteDeviceStatus) model.DeviceStatus {
 	var totalFiles int64
 	var totalDirs int64
-	var totalBytes int64
+	var totalBytes model.ByteCount
 	for _, folder := range folders {
 		totalFiles += folder.LocalFiles
 		totalDirs += max(1, folder.LocalFiles/2)
 		totalBytes += folder.LocalBytes
 	}
 
-	downloadBPS := (2.3 + float64((tick*3)%10)/10.0) * mib
-	uploadBPS := (145 + float64((tick*17)%115)) * kib
+	// Traffic only flows while at least one remote is connected.
+	var downloadBPS, uploadBPS float64
+	var sessionIn, sessionOut int64
+	if slices.ContainsFunc(remotes, func(remote model.RemoteDeviceStatus) bool { return remote.Connected }) {
+		downloadBPS = (2.3 + float64((tick*3)%10)/10.0) * mib
+		uploadBPS = (145 + float64((tick*17)%115)) * kib
+		// Roughly the average rates over every poll so far.
+		elapsed := float64(tick) * pollInterval.Seconds()
+		sessionIn = int64(elapsed * 2.7 * mib)
+		sessionOut = int64(elapsed * 200 * kib)
+	}
+	// Each connected remote carries an equal share of the traffic.
+	connected := 0
+	for _, remote := range remotes {
+		if remote.Connected {
+			connected++
+		}
+	}
+	for i := range remotes {
+		if remotes[i].Connected {
+			remotes[i].DownloadBPS = downloadBPS / float64(connected)
+			remotes[i].UploadBPS = uploadBPS / float64(connected)
+		}
+	}
 	uptime := now.Sub(startAt).Seconds() + float64(tick)*pollInterval.Seconds()
 
 	listenersTotal := 2
@@ -135,10 +301,12 @@ func buildDevice(now time.Time, tick int, startAt time.Time, pollInterval time.D
 		listenersOK = 1
 	}
 
-	discoveryTotal := 5
-	discoveryOK := 4
-	if tick%19 == 0 {
-		discoveryOK = 3
+	discovery := demoDiscovery(tick)
+	discoveryOK := 0
+	for _, method := range discovery {
+		if method.OK {
+			discoveryOK++
+		}
 	}
 
 	return model.DeviceStatus{
@@ -148,29 +316,68 @@ func buildDevice(now time.Time, tick int, startAt time.Time, pollInterval time.D
 		UptimeS:         int64(uptime),
 		DownloadBPS:     downloadBPS,
 		UploadBPS:       uploadBPS,
+		SessionInBytes:  model.ByteCount(sessionIn),
+		SessionOutBytes: model.ByteCount(sessionOut),
 		LocalFilesTotal: totalFiles,
 		LocalDirsTotal:  totalDirs,
 		LocalBytesTotal: totalBytes,
 		ListenersOK:     listenersOK,
 		ListenersTotal:  listenersTotal,
 		DiscoveryOK:     discoveryOK,
-		DiscoveryTotal:  discoveryTotal,
+		DiscoveryTotal:  len(discovery),
+
+		DiscoveryDetails: discovery,
+		// Every demo remote is taken to have been connected recently.
+		RemotesConnectedPeak: len(remotes),
+		OverallCompletionPct: model.OverallCompletion(folders),
 	}
 }
 
-func buildFolders(now time.Time, tick int) []model.FolderStatus {
-	seeds := []folderSeed{
-		{"folder-pictures", "Pictures", "/sync/Pictures", "local", 182, 136 * gib, 100, 0, 9},
-		{"folder-documents", "Documents", "/sync/Documents", "idle", 96, 42 * gib, 100, 0, 0},
-		{"folder-media", "Media", "/sync/Media", "syncing", 214, 328 * gib, 35, 3, 0},
-		{"folder-music", "Music", "/sync/Music", "syncing", 484, 78 * gib, 64, 4, 0},
-		{"folder-videos", "Videos", "/sync/Videos", "error", 33, 512 * gib, 73, 0, 0},
-		{"folder-downloads", "Downloads", "/sync/Downloads", "scanning", 127, 58 * gib, 100, 0, 0},
-		{"folder-projects", "Projects", "/sync/Projects", "syncing", 71, 24 * gib, 12, 5, 0},
-		{"folder-backups", "Backups", "/sync/Backups", "paused", 65, 910 * gib, 100, 0, 0},
-		{"folder-books", "Books", "/sync/Books", "local", 143, 19 * gib, 100, 0, 3},
-		{"folder-taxes", "Taxes", "/sync/Taxes", "idle", 22, 4 * gib, 100, 0, 0},
+func folderSeeds(scenario string) []folderSeed {
+	switch scenario {
+	case model.DemoScenarioEmpty:
+		return nil
+	case model.DemoScenarioHealthy:
+		return []folderSeed{
+			{"folder-pictures", "Pictures", "/sync/Pictures", "idle", 182, 136 * gib, 100, 0, 0},
+			{"folder-documents", "Documents", "/sync/Documents", "idle", 96, 42 * gib, 100, 0, 0},
+			{"folder-media", "Media", "/sync/Media", "idle", 214, 328 * gib, 100, 0, 0},
+			{"folder-music", "Music", "/sync/Music", "idle", 484, 78 * gib, 100, 0, 0},
+			{"folder-projects", "Projects", "/sync/Projects", "idle", 71, 24 * gib, 100, 0, 0},
+			{"folder-taxes", "Taxes", "/sync/Taxes", "idle", 22, 4 * gib, 100, 0, 0},
+		}
+	case model.DemoScenarioDegraded:
+		return []folderSeed{
+			{"folder-pictures", "Pictures", "/sync/Pictures", "local", 182, 136 * gib, 100, 0, 27},
+			{"folder-documents", "Documents", "/sync/Documents", "error", 96, 42 * gib, 72, 0, 0},
+			{"folder-media", "Media", "/sync/Media", "syncing", 214, 328 * gib, 8, 1, 0},
+			{"folder-videos", "Videos", "/sync/Videos", "error", 33, 512 * gib, 73, 0, 0},
+			{"folder-projects", "Projects", "/sync/Projects", "syncing", 71, 24 * gib, 21, 2, 0},
+			{"folder-backups", "Backups", "/sync/Backups", "paused", 65, 910 * gib, 100, 0, 0},
+		}
+	default:
+		return []folderSeed{
+			{"folder-pictures", "Pictures", "/sync/Pictures", "local", 182, 136 * gib, 100, 0, 9},
+			{"folder-documents", "Documents", "/sync/Documents", "idle", 96, 42 * gib, 100, 0, 0},
+			{"folder-media", "Media", "/sync/Media", "syncing", 214, 328 * gib, 35, 3, 0},
+			{"folder-music", "Music", "/sync/Music", "syncing", 484, 78 * gib, 64, 4, 0},
+			{"folder-videos", "Videos", "/sync/Videos", "error", 33, 512 * gib, 73, 0, 0},
+			{"folder-downloads", "Downloads", "/sync/Downloads", "scanning", 127, 58 * gib, 100, 0, 0},
+			{"folder-projects", "Projects", "/sync/Projects", "syncing", 71, 24 * gib, 12, 5, 0},
+			{"folder-backups", "Backups", "/sync/Backups", "paused", 65, 910 * gib, 100, 0, 0},
+			{"folder-books", "Books", "/sync/Books", "local", 143, 19 * gib, 100, 0, 3},
+			{"folder-taxes", "Taxes", "/sync/Taxes", "idle", 22, 4 * gib, 100, 0, 0},
+		}
 	}
+}
+
+func buildFolders(now time.Time, tick int, scenario string, count int) []model.FolderStatus {
+	seeds := resizeSeeds(folderSeeds(scenario), folderSeeds(model.DemoScenarioMixed), count, func(seed folderSeed, n int) folderSeed {
+		seed.ID = fmt.Sprintf("%s-%d", seed.ID, n)
+		seed.Label = fmt.Sprintf("%s %d", seed.Label, n)
+		seed.Path = fmt.Sprintf("%s %d", seed.Path, n)
+		return seed
+	})
 
 	folders := make([]model.FolderStatus, 0, len(seeds))
 	for idx, seed := range seeds {
@@ -182,6 +389,8 @@ func buildFolders(now time.Time, tick int) []model.FolderStatus {
 		localBytes := seed.GlobalBytes
 		localFiles := seed.GlobalFiles
 		completion := 100.0
+		folderType := "sendreceive"
+		var scanProgressPct *float64
 
 		switch seed.Mode {
 		case "syncing":
@@ -206,11 +415,18 @@ func buildFolders(now time.Time, tick int) []model.FolderStatus {
 			}
 		case "local":
 			state = "idle"
+			folderType = "receiveonly"
 			localChanges = max(1, seed.LocalChanges+int64(tick%3))
 		case "paused":
 			state = "paused"
 		case "scanning":
+			// Waits for a scan slot every sixth tick, then scans in steps.
 			state = "scan-waiting"
+			if step := (tick + idx) % 6; step > 0 {
+				state = "scanning"
+				pct := float64(step*19 - idx%5)
+				scanProgressPct = &pct
+			}
 		case "error":
 			state = "error"
 			completion = 72
@@ -219,42 +435,175 @@ func buildFolders(now time.Time, tick int) []model.FolderStatus {
 			localBytes = max(0, seed.GlobalBytes-needBytes)
 		}
 
+		needFiles, needDirectories, needSymlinks, needDeletes := demoNeedBreakdown(needItems, state)
+
+		diskFree, diskTotal := demoDisk(seed.ID, tick, scenario)
+		diskFreePct := 100 * float64(diskFree) / float64(diskTotal)
+		diskFreeBytes := model.ByteCount(diskFree)
+
 		lastScan := now.Add(-time.Duration((idx*13+tick)%170) * time.Minute).UTC()
-		completionCopy := completion
+		// Like the real collector, paused folders report no completion.
+		var completionPct *float64
+		if state != "paused" {
+			completionPct = &completion
+		}
 		folders = append(folders, model.FolderStatus{
 			ID:                seed.ID,
 			Label:             seed.Label,
 			Path:              seed.Path,
+			Type:              folderType,
 			State:             state,
 			GlobalFiles:       seed.GlobalFiles,
 			LocalFiles:        localFiles,
-			GlobalBytes:       globalBytes,
-			LocalBytes:        localBytes,
+			GlobalBytes:       model.ByteCount(globalBytes),
+			LocalBytes:        model.ByteCount(localBytes),
 			NeedItems:         needItems,
-			NeedBytes:         needBytes,
+			NeedBytes:         model.ByteCount(needBytes),
+			NeedFiles:         needFiles,
+			NeedDirectories:   needDirectories,
+			NeedSymlinks:      needSymlinks,
+			NeedDeletes:       needDeletes,
 			LocalChangesItems: localChanges,
-			CompletionPct:     &completionCopy,
+			LocalChangesBytes: model.ByteCount(localChanges * 37 * mib),
+			CompletionPct:     completionPct,
+			ScanProgressPct:   scanProgressPct,
 			LastScanAt:        &lastScan,
+			DiskFreeBytes:     &diskFreeBytes,
+			DiskFreePct:       &diskFreePct,
+			WatcherEnabled:    true,
+			WatcherError:      demoWatcherError(seed.ID, scenario),
+			SharedWith:        1 + idx%3,
 		})
 	}
 
 	return folders
 }
 
+// demoDisk places most folders on one roomy shared disk, and Videos and
+// Backups on their own nearly full disks. The healthy scenario keeps every
+// folder on the roomy disk; the degraded one puts them all on a nearly full one.
+func demoDisk(folderID string, tick int, scenario string) (int64, int64) {
+	switch scenario {
+	case model.DemoScenarioHealthy:
+		return 1100*gib - int64(tick%13)*gib, 4 * tib
+	case model.DemoScenarioDegraded:
+		return 6*gib - int64(tick%5)*256*mib, 2 * tib
+	}
+
+	switch folderID {
+	case "folder-videos":
+		return 14*gib - int64(tick%5)*256*mib, 1 * tib
+	case "folder-backups":
+		return 48 * gib, 2 * tib
+	default:
+		return 1100*gib - int64(tick%13)*gib, 4 * tib
+	}
+}
+
+// demoDiscovery reports five discovery methods. IPv6 global discovery always
+// fails, as on a host without IPv6, and local IPv6 discovery fails now and then.
+func demoDiscovery(tick int) []model.DiscoveryMethodStatus {
+	unreachable := "Post \"https://discovery-v6.syncthing.net/v2/\": dial tcp [2001:db8::1]:443: connect: network is unreachable"
+	methods := []model.DiscoveryMethodStatus{
+		{Name: "IPv4 local", OK: true},
+		{Name: "IPv6 local", OK: true},
+		{Name: "global@https://discovery-v4.syncthing.net/v2/", OK: true},
+		{Name: "global@https://discovery-v6.syncthing.net/v2/", Error: &unreachable},
+		{Name: "global@https://discovery.syncthing.net/v2/", OK: true},
+	}
+	if tick%19 == 0 {
+		multicast := "write udp [ff12::8384]:21027: sendto: no route to host"
+		methods[1] = model.DiscoveryMethodStatus{Name: "IPv6 local", Error: &multicast}
+	}
+	return methods
+}
+
+// demoNeedBreakdown splits needItems into files, directories, symlinks and
+// deletes. Folders in error are mostly stuck on deletes.
+func demoNeedBreakdown(needItems int64, state string) (files, dirs, symlinks, deletes int64) {
+	if state == "error" {
+		deletes = needItems * 2 / 3
+	} else {
+		deletes = needItems / 8
+	}
+	dirs = needItems / 16
+	symlinks = needItems / 64
+	files = needItems - deletes - dirs - symlinks
+	return files, dirs, symlinks, deletes
+}
+
+// demoWatcherError fails the Downloads watcher in the mixed scenario, as when
+// the inotify watch limit is too low for a large tree.
+func demoWatcherError(folderID, scenario string) *string {
+	if scenario != model.DemoScenarioMixed || folderID != "folder-downloads" {
+		return nil
+	}
+	errText := "failed to set up inotify handler. Please increase inotify limits"
+	return &errText
+}
+
+// resizeSeeds returns exactly count seeds, cycling through seeds, or through
+// fallback when the scenario has none. Later rounds are renamed by copyOf so
+// IDs stay unique. A negative count returns seeds unchanged.
+func resizeSeeds[S any](seeds, fallback []S, count int, copyOf func(S, int) S) []S {
+	if count < 0 {
+		return seeds
+	}
+	if len(seeds) == 0 {
+		seeds = fallback
+	}
+	resized := make([]S, 0, count)
+	for i := range count {
+		seed := seeds[i%len(seeds)]
+		if round := i / len(seeds); round > 0 {
+			seed = copyOf(seed, round+1)
+		}
+		resized = append(resized, seed)
+	}
+	return resized
+}
+
 type remoteSeed struct {
 	ID      string
 	Name    string
 	Address string
 	Mode    string
+	Error   string
 }
 
-func buildRemotes(now time.Time, tick int) []model.RemoteDeviceStatus {
-	seeds := []remoteSeed{
-		{"ATTIC-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Attic", "192.168.10.24:22000", "up"},
-		{"DESK-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Desk", "192.168.10.42:22000", "up"},
-		{"BACKPACK-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Backpack", "100.88.14.7:22000", "flap"},
-		{"KEYRING-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Keyring", "10.8.0.18:22000", "down"},
+func remoteSeeds(scenario string) []remoteSeed {
+	switch scenario {
+	case model.DemoScenarioEmpty:
+		return nil
+	case model.DemoScenarioHealthy:
+		return []remoteSeed{
+			{"ATTIC-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Attic", "tcp://192.168.10.24:22000", "up", ""},
+			{"DESK-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Desk", "tcp://192.168.10.42:22000", "up", ""},
+			{"BACKPACK-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Backpack", "quic://100.88.14.7:22000", "up", ""},
+		}
+	case model.DemoScenarioDegraded:
+		return []remoteSeed{
+			{"ATTIC-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Attic", "tcp://192.168.10.24:22000", "down", "dial tcp 192.168.10.24:22000: connect: no route to host"},
+			{"DESK-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Desk", "tcp://192.168.10.42:22000", "down", "dial tcp 192.168.10.42:22000: connect: connection refused"},
+			{"BACKPACK-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Backpack", "relay://100.88.14.7:22067", "down", "connection reset by peer"},
+			{"KEYRING-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Keyring", "tcp://10.8.0.18:22000", "down", "dial tcp 10.8.0.18:22000: i/o timeout"},
+		}
+	default:
+		return []remoteSeed{
+			{"ATTIC-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Attic", "tcp://192.168.10.24:22000", "up", ""},
+			{"DESK-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Desk", "tcp://192.168.10.42:22000", "up", ""},
+			{"BACKPACK-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Backpack", "relay://100.88.14.7:22067", "flap", "connection reset by peer"},
+			{"KEYRING-DEMO-J24XQXQ-HC2SY5M-NUQ6R7L-W7K6WTV-J5Z62DW-ZZQKAMA-2YBDAQH", "Keyring", "tcp://10.8.0.18:22000", "down", "dial tcp 10.8.0.18:22000: i/o timeout"},
+		}
 	}
+}
+
+func buildRemotes(now time.Time, tick int, scenario string, count int) []model.RemoteDeviceStatus {
+	seeds := resizeSeeds(remoteSeeds(scenario), remoteSeeds(model.DemoScenarioMixed), count, func(seed remoteSeed, n int) remoteSeed {
+		seed.ID = fmt.Sprintf("%s-%d", seed.ID, n)
+		seed.Name = fmt.Sprintf("%s %d", seed.Name, n)
+		return seed
+	})
 
 	remotes := make([]model.RemoteDeviceStatus, 0, len(seeds))
 	for idx, seed := range seeds {
@@ -268,15 +617,25 @@ func buildRemotes(now time.Time, tick int) []model.RemoteDeviceStatus {
 
 		lastSeen := now.Add(-time.Duration((idx+1)*(tick%5+1)) * time.Minute).UTC()
 
+		var lastError *string
+		if !connected && seed.Error != "" {
+			errText := seed.Error
+			lastError = &errText
+		}
+
+		connScheme, connHost, connPort := model.SplitConnAddress(seed.Address)
 		remotes = append(remotes, model.RemoteDeviceStatus{
 			ID:         seed.ID,
 			Name:       seed.Name,
 			Connected:  connected,
 			Address:    seed.Address,
+			ConnScheme: connScheme,
+			ConnHost:   connHost,
+			ConnPort:   connPort,
 			LastSeenAt: &lastSeen,
+			LastError:  lastError,
 		})
 	}
 
 	return remotes
 }
-
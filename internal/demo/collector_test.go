@@ -1,12 +1,15 @@
 package demo
 
 import (
+	"strings"
 	"testing"
 	"time"
+
+	"syncthing-dashboard/internal/model"
 )
 
 func TestDemoCollectorProducesRichSnapshot(t *testing.T) {
-	c := NewCollector(5 * time.Second)
+	c := NewCollector(Options{PollInterval: 5 * time.Second})
 	c.refresh()
 
 	snapshot, ok := c.Snapshot()
@@ -34,6 +37,9 @@ func TestDemoCollectorProducesRichSnapshot(t *testing.T) {
 		}
 		if folder.LocalChangesItems > 0 {
 			hasLocalChanges = true
+			if folder.Type != "receiveonly" {
+				t.Fatalf("expected folder %s with local changes to be receiveonly, got %q", folder.ID, folder.Type)
+			}
 		}
 		if folder.State == "error" {
 			hasError = true
@@ -66,10 +72,13 @@ func TestDemoCollectorProducesRichSnapshot(t *testing.T) {
 	if len(snapshot.Alerts) == 0 {
 		t.Fatalf("expected alerts in demo snapshot")
 	}
+	if pct := snapshot.Device.OverallCompletionPct; pct == nil || *pct <= 0 || *pct >= 100 {
+		t.Fatalf("expected a partial overall completion in the mixed demo, got %v", pct)
+	}
 }
 
 func TestDemoCollectorProgressMoves(t *testing.T) {
-	c := NewCollector(5 * time.Second)
+	c := NewCollector(Options{PollInterval: 5 * time.Second})
 	c.refresh()
 	first, ok := c.Snapshot()
 	if !ok {
@@ -108,3 +117,245 @@ func TestDemoCollectorProgressMoves(t *testing.T) {
 		t.Fatalf("expected demo progress to evolve over time")
 	}
 }
+
+func TestDemoCollectorScanProgressMoves(t *testing.T) {
+	c := NewCollector(Options{PollInterval: 5 * time.Second})
+	seen := make(map[float64]bool)
+	waited := false
+	for range 6 {
+		c.refresh()
+		snapshot, _ := c.Snapshot()
+		for _, folder := range snapshot.Folders {
+			if folder.ID != "folder-downloads" {
+				continue
+			}
+			switch {
+			case folder.State == "scanning" && folder.ScanProgressPct != nil:
+				seen[*folder.ScanProgressPct] = true
+			case folder.State != "scanning" && folder.ScanProgressPct == nil:
+				waited = true
+			default:
+				t.Fatalf("expected scan progress only while scanning, got state %q progress %v", folder.State, folder.ScanProgressPct)
+			}
+		}
+	}
+	if len(seen) < 2 || !waited {
+		t.Fatalf("expected moving scan progress between waits, got %v (waited %t)", seen, waited)
+	}
+}
+
+func TestDemoCollectorHonorsStaleAfter(t *testing.T) {
+	c := NewCollector(Options{PollInterval: 5 * time.Second, StaleAfter: time.Minute})
+	c.refresh()
+
+	c.snapshot.GeneratedAt = time.Now().UTC().Add(-30 * time.Second)
+	snapshot, _ := c.Snapshot()
+	if snapshot.Stale {
+		t.Fatalf("expected demo snapshot inside the stale window to be fresh")
+	}
+
+	c.snapshot.GeneratedAt = time.Now().UTC().Add(-90 * time.Second)
+	snapshot, _ = c.Snapshot()
+	if !snapshot.Stale {
+		t.Fatalf("expected demo snapshot outside the stale window to be stale")
+	}
+}
+
+func TestDemoCollectorReportsNearlyFullDisks(t *testing.T) {
+	c := NewCollector(Options{PollInterval: 5 * time.Second, DiskSpace: model.DiskSpaceThresholds{MinFreePct: 5}})
+	c.refresh()
+
+	snapshot, _ := c.Snapshot()
+	lowDisk := make(map[string]bool)
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "LOW_DISK_SPACE" {
+			lowDisk[alert.SubjectID] = true
+		}
+	}
+	if len(lowDisk) != 2 || !lowDisk["folder-videos"] || !lowDisk["folder-backups"] {
+		t.Fatalf("expected LOW_DISK_SPACE for the two nearly full demo folders, got %v", lowDisk)
+	}
+}
+
+func TestDemoCollectorHealthyScenario(t *testing.T) {
+	c := NewCollector(Options{PollInterval: 5 * time.Second, Scenario: model.DemoScenarioHealthy, DiskSpace: model.DiskSpaceThresholds{MinFreePct: 5}})
+	for range 30 {
+		c.refresh()
+
+		snapshot, _ := c.Snapshot()
+		if len(snapshot.Folders) == 0 || len(snapshot.Remotes) == 0 {
+			t.Fatalf("expected healthy scenario to have folders and remotes")
+		}
+		if alerts := withoutDemoAlert(snapshot.Alerts); len(alerts) != 0 {
+			t.Fatalf("expected no alerts in healthy scenario at tick %d, got %+v", c.tick, snapshot.Alerts)
+		}
+		for _, folder := range snapshot.Folders {
+			if folder.State != "idle" || folder.NeedBytes != 0 || folder.LocalChangesItems != 0 {
+				t.Fatalf("expected healthy folder %s to be idle and in sync, got %+v", folder.ID, folder)
+			}
+		}
+		for _, remote := range snapshot.Remotes {
+			if !remote.Connected || remote.LastError != nil {
+				t.Fatalf("expected healthy remote %s to be connected", remote.Name)
+			}
+		}
+		if snapshot.Health != model.HealthOK {
+			t.Fatalf("expected ok health in healthy scenario, got %q", snapshot.Health)
+		}
+	}
+}
+
+func TestDemoCollectorDegradedScenario(t *testing.T) {
+	c := NewCollector(Options{PollInterval: 5 * time.Second, Scenario: model.DemoScenarioDegraded, DiskSpace: model.DiskSpaceThresholds{MinFreePct: 5}})
+	c.refresh()
+
+	snapshot, _ := c.Snapshot()
+	for _, remote := range snapshot.Remotes {
+		if remote.Connected {
+			t.Fatalf("expected degraded remote %s to be disconnected", remote.Name)
+		}
+	}
+	if snapshot.Device.DownloadBPS != 0 || snapshot.Device.UploadBPS != 0 {
+		t.Fatalf("expected no traffic with every remote disconnected")
+	}
+
+	codes := make(map[string]int)
+	var hasCritical bool
+	for _, alert := range snapshot.Alerts {
+		codes[alert.Code]++
+		if alert.Severity == "critical" {
+			hasCritical = true
+		}
+	}
+	if !hasCritical {
+		t.Fatalf("expected a critical alert in degraded scenario, got %+v", snapshot.Alerts)
+	}
+	if snapshot.Health != model.HealthDegraded {
+		t.Fatalf("expected degraded health in degraded scenario, got %q", snapshot.Health)
+	}
+	if codes["LOW_DISK_SPACE"] != len(snapshot.Folders) {
+		t.Fatalf("expected LOW_DISK_SPACE for every degraded folder, got %d of %d", codes["LOW_DISK_SPACE"], len(snapshot.Folders))
+	}
+}
+
+func TestDemoCollectorTruncatesAlerts(t *testing.T) {
+	c := NewCollector(Options{PollInterval: 5 * time.Second, Scenario: model.DemoScenarioDegraded, MaxAlerts: 2})
+	c.refresh()
+
+	snapshot, _ := c.Snapshot()
+	alerts := withoutDemoAlert(snapshot.Alerts)
+	if len(alerts) != 3 {
+		t.Fatalf("expected two alerts plus a summary, got %+v", snapshot.Alerts)
+	}
+	if alerts[0].Severity != "critical" || alerts[2].Code != "ALERTS_TRUNCATED" {
+		t.Fatalf("expected most severe alerts then a truncation summary, got %+v", snapshot.Alerts)
+	}
+	if snapshot.Alerts[0].Code != "DEMO_MODE" {
+		t.Fatalf("expected DEMO_MODE to survive truncation, got %+v", snapshot.Alerts)
+	}
+}
+
+func TestDemoCollectorEmptyScenario(t *testing.T) {
+	c := NewCollector(Options{PollInterval: 5 * time.Second, Scenario: model.DemoScenarioEmpty})
+	c.refresh()
+
+	snapshot, ok := c.Snapshot()
+	if !ok {
+		t.Fatalf("expected snapshot to be available")
+	}
+	if alerts := withoutDemoAlert(snapshot.Alerts); len(snapshot.Folders) != 0 || len(snapshot.Remotes) != 0 || len(alerts) != 0 {
+		t.Fatalf("expected no folders, remotes or alerts, got %d/%d/%d", len(snapshot.Folders), len(snapshot.Remotes), len(alerts))
+	}
+	if snapshot.Device.LocalBytesTotal != 0 || snapshot.Device.DownloadBPS != 0 || snapshot.Device.OverallCompletionPct != nil {
+		t.Fatalf("expected empty device totals, got %+v", snapshot.Device)
+	}
+}
+
+func TestDemoCollectorUnknownScenarioFallsBackToMixed(t *testing.T) {
+	c := NewCollector(Options{Scenario: "chaotic"})
+	if c.scenario != model.DemoScenarioMixed {
+		t.Fatalf("expected unknown scenario to fall back to %q, got %q", model.DemoScenarioMixed, c.scenario)
+	}
+}
+
+func TestDemoCollectorReportsFailedWatcher(t *testing.T) {
+	c := NewCollector(Options{PollInterval: 5 * time.Second})
+	c.refresh()
+
+	snapshot, _ := c.Snapshot()
+	var failed []string
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "WATCHER_FAILED" {
+			failed = append(failed, alert.SubjectID)
+		}
+	}
+	if len(failed) != 1 || failed[0] != "folder-downloads" {
+		t.Fatalf("expected WATCHER_FAILED for the downloads demo folder, got %v", failed)
+	}
+}
+
+func TestDemoCollectorFolderAndRemoteCounts(t *testing.T) {
+	zero, folders, remotes := 0, 23, 9
+	c := NewCollector(Options{PollInterval: 5 * time.Second, Folders: &zero})
+	c.refresh()
+
+	snapshot, ok := c.Snapshot()
+	if !ok || !snapshot.SourceOnline {
+		t.Fatalf("expected an online snapshot with zero folders")
+	}
+	if len(snapshot.Folders) != 0 {
+		t.Fatalf("expected no folders, got %d", len(snapshot.Folders))
+	}
+	if len(snapshot.Remotes) != 4 {
+		t.Fatalf("expected the default 4 remotes, got %d", len(snapshot.Remotes))
+	}
+	for _, alert := range snapshot.Alerts {
+		if strings.HasPrefix(alert.SubjectID, "folder-") {
+			t.Fatalf("expected no folder alerts, got %+v", alert)
+		}
+	}
+
+	c = NewCollector(Options{PollInterval: 5 * time.Second, Scenario: model.DemoScenarioEmpty, Folders: &folders, Remotes: &remotes})
+	c.refresh()
+	snapshot, _ = c.Snapshot()
+	if len(snapshot.Folders) != folders || len(snapshot.Remotes) != remotes {
+		t.Fatalf("expected %d folders and %d remotes, got %d and %d", folders, remotes, len(snapshot.Folders), len(snapshot.Remotes))
+	}
+	seen := make(map[string]bool)
+	for _, folder := range snapshot.Folders {
+		if seen[folder.ID] {
+			t.Fatalf("expected unique folder IDs, got %s twice", folder.ID)
+		}
+		seen[folder.ID] = true
+	}
+}
+
+func TestDemoCollectorMarksSnapshotsAsDemo(t *testing.T) {
+	c := NewCollector(Options{PollInterval: 5 * time.Second, SuppressSeverities: []string{"info"}})
+	c.refresh()
+
+	snapshot, _ := c.Snapshot()
+	if !snapshot.Demo {
+		t.Fatalf("expected demo=true on demo snapshots")
+	}
+	var demoAlerts []model.Alert
+	for _, alert := range snapshot.Alerts {
+		if alert.Code == "DEMO_MODE" {
+			demoAlerts = append(demoAlerts, alert)
+		}
+	}
+	if len(demoAlerts) != 1 || demoAlerts[0].Severity != "info" || demoAlerts[0].Message != "Showing synthetic demonstration data" {
+		t.Fatalf("expected one DEMO_MODE info alert even with info suppressed, got %+v", snapshot.Alerts)
+	}
+}
+
+// withoutDemoAlert drops the DEMO_MODE notice every demo snapshot carries.
+func withoutDemoAlert(alerts []model.Alert) []model.Alert {
+	var out []model.Alert
+	for _, alert := range alerts {
+		if alert.Code != "DEMO_MODE" {
+			out = append(out, alert)
+		}
+	}
+	return out
+}
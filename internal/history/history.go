@@ -0,0 +1,93 @@
+// Package history keeps a bounded, in-memory trail of recent snapshot
+// summaries for short-term troubleshooting.
+package history
+
+import (
+	"sync"
+	"time"
+
+	"syncthing-dashboard/internal/model"
+)
+
+// Entry is a trimmed summary of one snapshot.
+type Entry struct {
+	GeneratedAt  time.Time     `json:"generated_at"`
+	SourceOnline bool          `json:"source_online"`
+	DownloadBPS  float64       `json:"download_bps"`
+	UploadBPS    float64       `json:"upload_bps"`
+	Folders      []FolderEntry `json:"folders"`
+}
+
+type FolderEntry struct {
+	ID            string   `json:"id"`
+	State         string   `json:"state"`
+	CompletionPct *float64 `json:"completion_pct"`
+}
+
+// FromSnapshot summarizes a snapshot into a history entry.
+func FromSnapshot(snapshot model.DashboardSnapshot) Entry {
+	folders := make([]FolderEntry, 0, len(snapshot.Folders))
+	for _, folder := range snapshot.Folders {
+		folders = append(folders, FolderEntry{
+			ID:            folder.ID,
+			State:         folder.State,
+			CompletionPct: folder.CompletionPct,
+		})
+	}
+
+	return Entry{
+		GeneratedAt:  snapshot.GeneratedAt,
+		SourceOnline: snapshot.SourceOnline,
+		DownloadBPS:  snapshot.Device.DownloadBPS,
+		UploadBPS:    snapshot.Device.UploadBPS,
+		Folders:      folders,
+	}
+}
+
+// Ring is a fixed-capacity, thread-safe ring of entries. The oldest entry is
+// overwritten once the ring is full. A zero-capacity ring records nothing.
+type Ring struct {
+	mu      sync.RWMutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+func NewRing(capacity int) *Ring {
+	return &Ring{entries: make([]Entry, max(0, capacity))}
+}
+
+func (r *Ring) Add(entry Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.entries) == 0 {
+		return
+	}
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Latest returns up to limit entries, newest first. A limit <= 0 returns all.
+func (r *Ring) Latest(limit int) []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.entries)
+	}
+	if limit > 0 && limit < count {
+		count = limit
+	}
+
+	out := make([]Entry, 0, count)
+	for i := 1; i <= count; i++ {
+		idx := (r.next - i + len(r.entries)) % len(r.entries)
+		out = append(out, r.entries[idx])
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package history
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRingReturnsNewestFirstAndWraps(t *testing.T) {
+	ring := NewRing(3)
+	base := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		ring.Add(Entry{GeneratedAt: base.Add(time.Duration(i) * time.Second)})
+	}
+
+	all := ring.Latest(0)
+	if len(all) != 3 {
+		t.Fatalf("expected ring to hold 3 entries, got %d", len(all))
+	}
+	for i, want := range []int{4, 3, 2} {
+		if !all[i].GeneratedAt.Equal(base.Add(time.Duration(want) * time.Second)) {
+			t.Fatalf("unexpected entry at %d: %s", i, all[i].GeneratedAt)
+		}
+	}
+
+	if got := ring.Latest(2); len(got) != 2 || !got[0].GeneratedAt.Equal(base.Add(4*time.Second)) {
+		t.Fatalf("unexpected limited entries: %+v", got)
+	}
+	if got := ring.Latest(10); len(got) != 3 {
+		t.Fatalf("expected limit above size to return all entries, got %d", len(got))
+	}
+}
+
+func TestRingPartiallyFilledAndZeroCapacity(t *testing.T) {
+	ring := NewRing(5)
+	if got := ring.Latest(0); len(got) != 0 {
+		t.Fatalf("expected empty ring, got %d entries", len(got))
+	}
+	ring.Add(Entry{SourceOnline: true})
+	if got := ring.Latest(0); len(got) != 1 || !got[0].SourceOnline {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+
+	disabled := NewRing(0)
+	disabled.Add(Entry{})
+	if got := disabled.Latest(0); len(got) != 0 {
+		t.Fatalf("expected zero-capacity ring to record nothing, got %d", len(got))
+	}
+}
+
+func TestRingConcurrentAccess(t *testing.T) {
+	ring := NewRing(10)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				ring.Add(Entry{GeneratedAt: time.Now()})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				_ = ring.Latest(5)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := ring.Latest(0); len(got) != 10 {
+		t.Fatalf("expected full ring, got %d", len(got))
+	}
+}
@@ -1,17 +1,93 @@
 package httpapi
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/http/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"syncthing-dashboard/internal/history"
+	"syncthing-dashboard/internal/humanize"
 	"syncthing-dashboard/internal/model"
+	"syncthing-dashboard/internal/version"
 	webstatic "syncthing-dashboard/web"
 )
 
 type snapshotReader interface {
 	Snapshot() (model.DashboardSnapshot, bool)
+	// Updated returns a channel closed when the next snapshot is published.
+	// Streaming endpoints wait on it rather than polling Snapshot.
+	Updated() <-chan struct{}
 	Ready() bool
+	History(limit int) []history.Entry
+}
+
+// snapshotRefresher is implemented by readers that can poll on demand.
+type snapshotRefresher interface {
+	RefreshNow(ctx context.Context) error
+}
+
+// refreshInterval is the least time between two /api/v1/refresh polls, across
+// all clients, since each one reaches Syncthing.
+const refreshInterval = 5 * time.Second
+
+// A /api/v1/dashboard?wait=true request is held for at most
+// Options.LongPollTimeout, or defaultLongPollTimeout when unset, and its write
+// deadline is pushed past that by longPollWriteSlack so the server's write
+// timeout does not cut it.
+const (
+	defaultLongPollTimeout = 30 * time.Second
+	longPollWriteSlack     = 10 * time.Second
+)
+
+// Options configures the API.
+type Options struct {
+	PageTitle    string
+	PageSubtitle string
+	// InstanceName identifies this dashboard to tools that aggregate several.
+	InstanceName string
+	PollInterval time.Duration
+	// LongPollTimeout is the longest a ?wait=true dashboard request is held
+	// waiting for a new snapshot. Zero uses 30s.
+	LongPollTimeout time.Duration
+	// Timezone is an IANA zone name the UI should render times in. Times in
+	// responses stay UTC; empty leaves rendering to the browser's zone.
+	Timezone string
+	// EffectiveConfig is served at /api/v1/config. It must already have its
+	// secrets redacted; nil leaves the endpoint answering 404.
+	EffectiveConfig any
+	// EnablePprof registers the net/http/pprof handlers under /debug/pprof/.
+	EnablePprof bool
+	// SeparateAdmin moves the health probes and pprof handlers off the main
+	// handler; they are then only served by AdminHandler.
+	SeparateAdmin bool
+	// RateLimit caps /api/v1/ requests per second from each client IP. Zero
+	// disables limiting.
+	RateLimit float64
+	// MaxConcurrent caps how many requests are served at once; past it
+	// requests get 503 with Retry-After. Health probes, WebSocket streams and
+	// long-polls are not counted. Zero means unlimited.
+	MaxConcurrent int
+	// AccessLog, when set, receives one line per request. Health probes are
+	// left out unless AccessLogProbes is set.
+	AccessLog       *slog.Logger
+	AccessLogProbes bool
+	// BasicUser and BasicPassword, when both set, require HTTP Basic auth on
+	// every route except /healthz and /readyz.
+	BasicUser     string
+	BasicPassword string
 }
 
 // API hosts the read-only dashboard endpoints and static UI.
@@ -19,32 +95,253 @@ type API struct {
 	reader       snapshotReader
 	pageTitle    string
 	pageSubtitle string
+	instanceName string
 	pollInterval time.Duration
+	longPoll     time.Duration
+	timezone     string
+	config       any
+	limiter      *rateLimiter
+	refreshes    *rateLimiter
+	inFlight     *concurrencyLimiter
+	auth         *basicAuth
+	accessLog    *slog.Logger
+	logProbes    bool
 	mux          *http.ServeMux
+	admin        *http.ServeMux
+
+	// closing is closed by Close to end WebSocket streams and long-polls,
+	// which the server's shutdown does not otherwise reach.
+	closing   chan struct{}
+	closeOnce sync.Once
 }
 
-func New(reader snapshotReader, pageTitle, pageSubtitle string, pollInterval time.Duration) *API {
+func New(reader snapshotReader, opts Options) *API {
 	api := &API{
 		reader:       reader,
-		pageTitle:    pageTitle,
-		pageSubtitle: pageSubtitle,
-		pollInterval: pollInterval,
+		pageTitle:    opts.PageTitle,
+		pageSubtitle: opts.PageSubtitle,
+		instanceName: opts.InstanceName,
+		pollInterval: opts.PollInterval,
+		longPoll:     opts.LongPollTimeout,
+		timezone:     opts.Timezone,
+		config:       opts.EffectiveConfig,
+		accessLog:    opts.AccessLog,
+		logProbes:    opts.AccessLogProbes,
+		refreshes:    newRateLimiter(1 / refreshInterval.Seconds()),
 		mux:          http.NewServeMux(),
+		admin:        http.NewServeMux(),
+		closing:      make(chan struct{}),
+	}
+	if api.longPoll <= 0 {
+		api.longPoll = defaultLongPollTimeout
+	}
+	if opts.RateLimit > 0 {
+		api.limiter = newRateLimiter(opts.RateLimit)
+	}
+	if opts.MaxConcurrent > 0 {
+		api.inFlight = newConcurrencyLimiter(opts.MaxConcurrent)
+	}
+	if opts.BasicUser != "" && opts.BasicPassword != "" {
+		api.auth = newBasicAuth(opts.BasicUser, opts.BasicPassword)
 	}
 
 	api.mux.HandleFunc("/api/v1/dashboard", api.handleDashboard)
-	api.mux.HandleFunc("/healthz", api.handleHealthz)
-	api.mux.HandleFunc("/readyz", api.handleReadyz)
+	api.mux.HandleFunc("/api/v1/dashboard/ws", api.handleDashboardWS)
+	api.mux.HandleFunc("/api/v1/alerts", api.handleAlerts)
+	api.mux.HandleFunc("/api/v1/remotes", api.handleRemotes)
+	api.mux.HandleFunc("/api/v1/folders.csv", api.handleFoldersCSV)
+	api.mux.HandleFunc("/api/v1/history", api.handleHistory)
+	api.mux.HandleFunc("/api/v1/status.txt", api.handleStatusText)
+	api.mux.HandleFunc("/api/v1/summary", api.handleSummary)
+	api.mux.HandleFunc("/api/v1/source/health", api.handleSourceHealth)
+	api.mux.HandleFunc("/api/v1/refresh", api.handleRefresh)
+	api.mux.HandleFunc("/api/v1/config", api.handleConfig)
+	// Unmatched /api/ paths get a JSON 404 instead of falling through to the
+	// file server.
+	api.mux.HandleFunc("/api/", notFound)
+	api.registerAdmin(api.admin, opts.EnablePprof)
+	if !opts.SeparateAdmin {
+		api.registerAdmin(api.mux, opts.EnablePprof)
+	}
+	api.mux.HandleFunc("/favicon.ico", handleFavicon)
 	api.mux.Handle("/", http.FileServer(http.FS(webstatic.Files)))
 
 	return api
 }
 
+// Close ends open WebSocket streams with a going-away close frame and answers
+// waiting long-polls with the current snapshot. Pass it to
+// http.Server.RegisterOnShutdown: hijacked connections are not tracked by the
+// server, so Shutdown would otherwise leave streams open until the process
+// exits. It is safe to call more than once.
+func (a *API) Close() {
+	a.closeOnce.Do(func() { close(a.closing) })
+}
+
+// registerAdmin adds the health probes and, with enablePprof, the pprof
+// handlers to mux.
+func (a *API) registerAdmin(mux *http.ServeMux, enablePprof bool) {
+	mux.HandleFunc("/healthz", a.handleHealthz)
+	mux.HandleFunc("/readyz", a.handleReadyz)
+	if enablePprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+}
+
+// AdminHandler serves only the health probes and, when enabled, pprof. It is
+// meant for an internal listener, so it skips basic auth and rate limiting.
+func (a *API) AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.accessLog != nil && (a.logProbes || !isProbePath(r.URL.Path)) {
+			a.logAccess(w, r, a.admin.ServeHTTP)
+			return
+		}
+		a.admin.ServeHTTP(w, r)
+	})
+}
+
 func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if a.accessLog != nil && (a.logProbes || !isProbePath(r.URL.Path)) {
+		a.logAccess(w, r, a.serve)
+		return
+	}
+	a.serve(w, r)
+}
+
+func (a *API) serve(w http.ResponseWriter, r *http.Request) {
+	if a.auth != nil && !isProbePath(r.URL.Path) && !a.auth.check(w, r) {
+		return
+	}
+	if a.limiter != nil && strings.HasPrefix(r.URL.Path, "/api/v1/") && !a.limiter.limit(w, r) {
+		return
+	}
+	// Slots are taken only after auth and rate limiting, so rejected
+	// requests cannot crowd out authenticated ones.
+	if a.inFlight != nil && !isProbePath(r.URL.Path) && !isStreamingRequest(r) {
+		if !a.inFlight.acquire() {
+			serverBusy(w)
+			return
+		}
+		defer a.inFlight.release()
+	}
 	a.mux.ServeHTTP(w, r)
 }
 
 func (a *API) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w)
+		return
+	}
+	w = headAware(w, r)
+
+	query := r.URL.Query()
+	wait := false
+	if raw := query.Get("wait"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, codeInvalidParameter, "wait must be true or false")
+			return
+		}
+		wait = parsed
+	}
+
+	// A long-poll's since is the snapshot_id the client already has; a plain
+	// request's is the generated_at of its last response. Each rejects the
+	// other's form rather than letting a long-poll given a timestamp return
+	// at once without ever matching.
+	if raw := query.Get("since"); raw != "" && wait && !model.IsSnapshotID(raw) {
+		writeError(w, http.StatusBadRequest, codeInvalidParameter, "since must be a snapshot_id when wait is true")
+		return
+	}
+	var since *time.Time
+	if raw := query.Get("since"); raw != "" && !wait {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, codeInvalidParameter, "since must be an RFC3339 timestamp")
+			return
+		}
+		since = &parsed
+	}
+
+	var snapshot model.DashboardSnapshot
+	var ok bool
+	if wait {
+		snapshot, ok = a.waitForSnapshot(w, r, query.Get("since"))
+		if r.Context().Err() != nil {
+			return
+		}
+	} else {
+		snapshot, ok = a.reader.Snapshot()
+	}
+	if !ok {
+		writeError(w, http.StatusServiceUnavailable, codeSnapshotUnavailable, "snapshot unavailable")
+		return
+	}
+
+	body, err := json.Marshal(a.dashboardResponse(snapshot))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to encode snapshot")
+		return
+	}
+
+	// no-store keeps intermediaries from caching, while the ETag still lets
+	// well-behaved pollers skip downloading an unchanged payload.
+	etag := payloadETag(body)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("ETag", etag)
+	w.Header().Set("X-Snapshot-Generated-At", snapshot.GeneratedAt.UTC().Format(time.RFC3339))
+	w.Header().Set("X-Snapshot-Stale", strconv.FormatBool(snapshot.Stale))
+	w.Header().Set("X-Snapshot-Age-Seconds", strconv.FormatInt(snapshotAgeSeconds(snapshot.GeneratedAt, time.Now()), 10))
+	if !wait && (etagMatches(r.Header.Get("If-None-Match"), etag) || (since != nil && !snapshot.GeneratedAt.After(*since))) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	body = append(body, '\n')
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// waitForSnapshot holds a long-poll until a snapshot whose ID differs from
+// sinceID is published, the long-poll timeout passes or the client goes away,
+// and returns the snapshot as it is then. It wakes on the reader's Updated
+// notifications, the same ones that drive the WebSocket stream.
+func (a *API) waitForSnapshot(w http.ResponseWriter, r *http.Request, sinceID string) (model.DashboardSnapshot, bool) {
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(a.longPoll + longPollWriteSlack))
+
+	timeout := time.NewTimer(a.longPoll)
+	defer timeout.Stop()
+	for {
+		updated := a.reader.Updated()
+		snapshot, ok := a.reader.Snapshot()
+		if ok && snapshot.SnapshotID != sinceID {
+			return snapshot, true
+		}
+		select {
+		case <-r.Context().Done():
+			return model.DashboardSnapshot{}, false
+		case <-timeout.C:
+			return a.reader.Snapshot()
+		case <-a.closing:
+			return a.reader.Snapshot()
+		case <-updated:
+		}
+	}
+}
+
+// snapshotAgeSeconds is how old a snapshot generated at generatedAt is at now,
+// in whole seconds and never negative.
+func snapshotAgeSeconds(generatedAt, now time.Time) int64 {
+	return max(0, int64(now.Sub(generatedAt)/time.Second))
+}
+
+func (a *API) handleAlerts(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		methodNotAllowed(w)
 		return
@@ -52,32 +349,239 @@ func (a *API) handleDashboard(w http.ResponseWriter, r *http.Request) {
 
 	snapshot, ok := a.reader.Snapshot()
 	if !ok {
-		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "snapshot unavailable"})
+		writeError(w, http.StatusServiceUnavailable, codeSnapshotUnavailable, "snapshot unavailable")
 		return
 	}
 
+	severities := queryValueSet(r, "severity")
+	codes := queryValueSet(r, "code")
+	alerts := make([]model.Alert, 0, len(snapshot.Alerts))
+	for _, alert := range snapshot.Alerts {
+		if len(severities) > 0 && !severities[strings.ToLower(alert.Severity)] {
+			continue
+		}
+		if len(codes) > 0 && !codes[strings.ToLower(alert.Code)] {
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	sort.SliceStable(alerts, func(i, j int) bool {
+		ri, rj := model.SeverityLevel(alerts[i].Severity), model.SeverityLevel(alerts[j].Severity)
+		if ri != rj {
+			return ri > rj
+		}
+		return alerts[i].Code < alerts[j].Code
+	})
+
 	w.Header().Set("Cache-Control", "no-store")
-	writeJSON(w, http.StatusOK, dashboardResponse{
-		DashboardSnapshot: snapshot,
-		PageTitle:         a.pageTitle,
-		PageSubtitle:      a.pageSubtitle,
-		PollIntervalMS:    a.pollInterval.Milliseconds(),
+	writeJSON(w, http.StatusOK, alertsResponse{
+		GeneratedAt: snapshot.GeneratedAt,
+		Count:       len(alerts),
+		Alerts:      alerts,
 	})
 }
 
-func (a *API) handleHealthz(w http.ResponseWriter, r *http.Request) {
+// handleRemotes lists remote devices, optionally only connected or only
+// disconnected ones. The unfiltered list puts disconnected devices first.
+func (a *API) handleRemotes(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		methodNotAllowed(w)
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+
+	var wantConnected *bool
+	if raw := r.URL.Query().Get("connected"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, codeInvalidParameter, "connected must be true or false")
+			return
+		}
+		wantConnected = &parsed
+	}
+
+	snapshot, ok := a.reader.Snapshot()
+	if !ok {
+		writeError(w, http.StatusServiceUnavailable, codeSnapshotUnavailable, "snapshot unavailable")
+		return
+	}
+
+	remotes := make([]model.RemoteDeviceStatus, 0, len(snapshot.Remotes))
+	for _, remote := range snapshot.Remotes {
+		if wantConnected == nil || remote.Connected == *wantConnected {
+			remotes = append(remotes, remote)
+		}
+	}
+	if wantConnected == nil {
+		model.SortRemotes(remotes, model.RemoteSortConnection)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, remotesResponse{
+		GeneratedAt: snapshot.GeneratedAt,
+		Count:       len(remotes),
+		Remotes:     remotes,
+	})
 }
 
-func (a *API) handleReadyz(w http.ResponseWriter, r *http.Request) {
+// queryValueSet collects comma-separated values of a query parameter,
+// lowercased for case-insensitive matching.
+func queryValueSet(r *http.Request, name string) map[string]bool {
+	values := make(map[string]bool)
+	for _, raw := range r.URL.Query()[name] {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+				values[value] = true
+			}
+		}
+	}
+	return values
+}
+
+var folderCSVHeader = []string{
+	"id", "label", "path", "state",
+	"global_files", "local_files", "global_bytes", "local_bytes", "need_bytes",
+	"completion_pct", "last_scan_at",
+}
+
+func (a *API) handleFoldersCSV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+
+	snapshot, ok := a.reader.Snapshot()
+	if !ok {
+		writeError(w, http.StatusServiceUnavailable, codeSnapshotUnavailable, "snapshot unavailable")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=folders.csv")
+	w.WriteHeader(http.StatusOK)
+
+	out := csv.NewWriter(w)
+	_ = out.Write(folderCSVHeader)
+	for _, folder := range snapshot.Folders {
+		completion := ""
+		if folder.CompletionPct != nil {
+			completion = strconv.FormatFloat(*folder.CompletionPct, 'f', -1, 64)
+		}
+		lastScan := ""
+		if folder.LastScanAt != nil {
+			lastScan = folder.LastScanAt.UTC().Format(time.RFC3339)
+		}
+		_ = out.Write([]string{
+			folder.ID,
+			folder.Label,
+			folder.Path,
+			folder.State,
+			strconv.FormatInt(folder.GlobalFiles, 10),
+			strconv.FormatInt(folder.LocalFiles, 10),
+			strconv.FormatInt(int64(folder.GlobalBytes), 10),
+			strconv.FormatInt(int64(folder.LocalBytes), 10),
+			strconv.FormatInt(int64(folder.NeedBytes), 10),
+			completion,
+			lastScan,
+		})
+	}
+	out.Flush()
+}
+
+func (a *API) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			writeError(w, http.StatusBadRequest, codeInvalidParameter, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+
+	entries := a.reader.History(limit)
+	if entries == nil {
+		entries = []history.Entry{}
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// handleStatusText serves a one-line key=value summary for scripts. Degraded
+// states are still 200; only a missing snapshot is an error.
+func (a *API) handleStatusText(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+
+	snapshot, ok := a.reader.Snapshot()
+	if !ok {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = io.WriteString(w, "snapshot unavailable\n")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, statusLine(snapshot)+"\n")
+}
+
+func statusLine(snapshot model.DashboardSnapshot) string {
+	summary := model.Summarize(snapshot)
+	syncing := summary.FoldersByState["syncing"] + summary.FoldersByState["sync-preparing"]
+
+	// Rates drop the unit's space so every field stays a single key=value token.
+	rate := func(bps float64) string {
+		return strings.ReplaceAll(humanize.BytesPerSecond(bps), " ", "")
+	}
+	return fmt.Sprintf("online=%t stale=%t folders=%d syncing=%d errors=%d remotes=%d/%d down=%s up=%s",
+		summary.SourceOnline, summary.Stale,
+		summary.FoldersTotal, syncing, summary.FoldersByState["error"],
+		summary.RemotesConnected, summary.RemotesTotal,
+		rate(summary.DownloadBPS), rate(summary.UploadBPS))
+}
+
+// handleSummary serves the top-line numbers of the current snapshot, small
+// enough for widgets that poll often.
+func (a *API) handleSummary(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		methodNotAllowed(w)
 		return
 	}
+
+	snapshot, ok := a.reader.Snapshot()
+	if !ok {
+		writeError(w, http.StatusServiceUnavailable, codeSnapshotUnavailable, "snapshot unavailable")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, model.Summarize(snapshot))
+}
+
+func (a *API) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w)
+		return
+	}
+	w = headAware(w, r)
+	writeJSON(w, http.StatusOK, map[string]any{"ok": true, "version": version.Version})
+}
+
+func (a *API) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w)
+		return
+	}
+	w = headAware(w, r)
 	if !a.reader.Ready() {
 		writeJSON(w, http.StatusServiceUnavailable, map[string]bool{"ready": false})
 		return
@@ -85,8 +589,164 @@ func (a *API) handleReadyz(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]bool{"ready": true})
 }
 
+// handleSourceHealth reports whether the last poll reached Syncthing. Unlike
+// /readyz it fails while only the fallback snapshot is being served.
+func (a *API) handleSourceHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+
+	snapshot, ok := a.reader.Snapshot()
+	resp := sourceHealthResponse{Online: ok && snapshot.SourceOnline}
+	if ok {
+		resp.LastError = snapshot.SourceError
+	}
+	// Before the first successful poll there is no good snapshot to date.
+	if ok && a.reader.Ready() {
+		generatedAt := snapshot.GeneratedAt
+		resp.LastGoodAt = &generatedAt
+	}
+
+	status := http.StatusOK
+	if !resp.Online {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, resp)
+}
+
+// handleRefresh polls Syncthing out of band and returns the resulting
+// snapshot, so a fix shows up without waiting for the next scheduled poll.
+func (a *API) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+
+	refresher, ok := a.reader.(snapshotRefresher)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, codeNotImplemented, "refresh not supported")
+		return
+	}
+	if allowed, retryAfter := a.refreshes.allow(""); !allowed {
+		tooManyRequests(w, retryAfter)
+		return
+	}
+	if err := refresher.RefreshNow(r.Context()); err != nil {
+		writeError(w, http.StatusServiceUnavailable, codeRefreshFailed, "refresh did not finish")
+		return
+	}
+
+	snapshot, ok := a.reader.Snapshot()
+	if !ok {
+		writeError(w, http.StatusServiceUnavailable, codeSnapshotUnavailable, "snapshot unavailable")
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, a.dashboardResponse(snapshot))
+}
+
+func (a *API) dashboardResponse(snapshot model.DashboardSnapshot) dashboardResponse {
+	return dashboardResponse{
+		DashboardSnapshot: snapshot,
+		PageTitle:         a.pageTitle,
+		PageSubtitle:      a.pageSubtitle,
+		InstanceName:      a.instanceName,
+		PollIntervalMS:    a.pollInterval.Milliseconds(),
+		ServerVersion:     version.Version,
+		Timezone:          a.timezone,
+	}
+}
+
+// handleConfig returns the dashboard's own effective configuration, with
+// secrets redacted by the caller of New.
+func (a *API) handleConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	if a.config == nil {
+		notFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, a.config)
+}
+
+// handleFavicon answers the browsers' default /favicon.ico request with the
+// SVG icon the page links to, rather than a 404 on every page load.
+func handleFavicon(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w)
+		return
+	}
+	icon, err := webstatic.Files.ReadFile("favicon.svg")
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	http.ServeContent(w, r, "favicon.svg", time.Time{}, bytes.NewReader(icon))
+}
+
+// headWriter drops the body of a HEAD response, keeping its status and
+// headers, so HEAD can share the GET code path.
+type headWriter struct {
+	http.ResponseWriter
+}
+
+func (headWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w headWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// headAware wraps w in a headWriter for HEAD requests.
+func headAware(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
+	if r.Method == http.MethodHead {
+		return headWriter{w}
+	}
+	return w
+}
+
+func notFound(w http.ResponseWriter, _ *http.Request) {
+	writeError(w, http.StatusNotFound, codeNotFound, "not found")
+}
+
 func methodNotAllowed(w http.ResponseWriter) {
-	writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed, "method not allowed")
+}
+
+// Error codes returned in errorResponse.Code. They are stable, unlike the
+// messages, so clients can branch on them.
+const (
+	codeSnapshotUnavailable = "SNAPSHOT_UNAVAILABLE"
+	codeMethodNotAllowed    = "METHOD_NOT_ALLOWED"
+	codeNotFound            = "NOT_FOUND"
+	codeInvalidParameter    = "INVALID_PARAMETER"
+	codeNotImplemented      = "NOT_IMPLEMENTED"
+	codeRefreshFailed       = "REFRESH_FAILED"
+	codeUnauthorized        = "UNAUTHORIZED"
+	codeForbidden           = "FORBIDDEN"
+	codeRateLimited         = "RATE_LIMITED"
+	codeServerBusy          = "SERVER_BUSY"
+	codeUpgradeRequired     = "UPGRADE_REQUIRED"
+	codeInternal            = "INTERNAL_ERROR"
+)
+
+// errorResponse is the body of every JSON error. Message keeps the "error"
+// key that responses used before codes were added.
+type errorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, errorResponse{Code: code, Message: message})
 }
 
 func writeJSON(w http.ResponseWriter, status int, payload any) {
@@ -95,9 +755,45 @@ func writeJSON(w http.ResponseWriter, status int, payload any) {
 	_ = json.NewEncoder(w).Encode(payload)
 }
 
+func payloadETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
 type dashboardResponse struct {
 	model.DashboardSnapshot
 	PageTitle      string `json:"page_title"`
 	PageSubtitle   string `json:"page_subtitle"`
+	InstanceName   string `json:"instance_name"`
 	PollIntervalMS int64  `json:"poll_interval_ms"`
+	ServerVersion  string `json:"server_version"`
+	Timezone       string `json:"timezone,omitempty"`
+}
+
+type remotesResponse struct {
+	GeneratedAt time.Time                  `json:"generated_at"`
+	Count       int                        `json:"count"`
+	Remotes     []model.RemoteDeviceStatus `json:"remotes"`
+}
+
+type sourceHealthResponse struct {
+	Online     bool       `json:"online"`
+	LastError  *string    `json:"last_error"`
+	LastGoodAt *time.Time `json:"last_good_at"`
+}
+
+type alertsResponse struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	Count       int           `json:"count"`
+	Alerts      []model.Alert `json:"alerts"`
 }
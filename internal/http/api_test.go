@@ -1,30 +1,59 @@
 package httpapi
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"syncthing-dashboard/internal/history"
 	"syncthing-dashboard/internal/model"
+	"syncthing-dashboard/internal/notify"
+	"syncthing-dashboard/internal/version"
 )
 
+var testOptions = Options{
+	PageTitle:    "Syncthing",
+	PageSubtitle: "Read-Only Dashboard",
+	InstanceName: "vault",
+	PollInterval: 5 * time.Second,
+}
+
 type fakeReader struct {
 	snapshot model.DashboardSnapshot
 	ok       bool
 	ready    bool
+	history  []history.Entry
 }
 
 func (f fakeReader) Snapshot() (model.DashboardSnapshot, bool) {
 	return f.snapshot, f.ok
 }
 
+// Updated never fires; fakeReader's snapshot does not change.
+func (f fakeReader) Updated() <-chan struct{} {
+	return nil
+}
+
 func (f fakeReader) Ready() bool {
 	return f.ready
 }
 
+func (f fakeReader) History(limit int) []history.Entry {
+	if limit > 0 && limit < len(f.history) {
+		return f.history[:limit]
+	}
+	return f.history
+}
+
 func TestDashboardEndpointReturnsSnapshot(t *testing.T) {
 	api := New(fakeReader{
 		snapshot: model.DashboardSnapshot{
@@ -33,7 +62,7 @@ func TestDashboardEndpointReturnsSnapshot(t *testing.T) {
 		},
 		ok:    true,
 		ready: true,
-	}, "Syncthing", "Read-Only Dashboard", 5*time.Second)
+	}, testOptions)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil)
 	rr := httptest.NewRecorder()
@@ -50,7 +79,9 @@ func TestDashboardEndpointReturnsSnapshot(t *testing.T) {
 		model.DashboardSnapshot
 		PageTitle      string `json:"page_title"`
 		PageSubtitle   string `json:"page_subtitle"`
+		InstanceName   string `json:"instance_name"`
 		PollIntervalMS int64  `json:"poll_interval_ms"`
+		ServerVersion  string `json:"server_version"`
 	}
 	if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
 		t.Fatalf("failed to decode payload: %v", err)
@@ -61,13 +92,161 @@ func TestDashboardEndpointReturnsSnapshot(t *testing.T) {
 	if payload.PageTitle != "Syncthing" || payload.PageSubtitle != "Read-Only Dashboard" {
 		t.Fatalf("unexpected page branding: %+v", payload)
 	}
+	if payload.InstanceName != "vault" {
+		t.Fatalf("unexpected instance name: %q", payload.InstanceName)
+	}
 	if payload.PollIntervalMS != 5000 {
 		t.Fatalf("unexpected poll interval ms: %d", payload.PollIntervalMS)
 	}
+	if payload.ServerVersion != version.Version {
+		t.Fatalf("unexpected server version: %q", payload.ServerVersion)
+	}
+}
+
+func TestHealthzReportsVersion(t *testing.T) {
+	api := New(fakeReader{}, testOptions)
+
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+
+	var payload struct {
+		OK      bool   `json:"ok"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if !payload.OK || payload.Version != version.Version {
+		t.Fatalf("unexpected healthz payload: %+v", payload)
+	}
+}
+
+func TestDashboardEndpointFreshnessHeaders(t *testing.T) {
+	api := New(fakeReader{
+		snapshot: model.DashboardSnapshot{
+			GeneratedAt:  time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC),
+			SourceOnline: true,
+			Stale:        true,
+		},
+		ok:    true,
+		ready: true,
+	}, testOptions)
+
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil))
+
+	if got := rr.Header().Get("X-Snapshot-Generated-At"); got != "2026-02-06T10:00:00Z" {
+		t.Fatalf("unexpected X-Snapshot-Generated-At: %q", got)
+	}
+	if got := rr.Header().Get("X-Snapshot-Stale"); got != "true" {
+		t.Fatalf("unexpected X-Snapshot-Stale: %q", got)
+	}
+	if rr.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("expected no-store cache header to be kept")
+	}
+}
+
+func TestDashboardEndpointReportsSnapshotAge(t *testing.T) {
+	api := New(fakeReader{
+		snapshot: model.DashboardSnapshot{GeneratedAt: time.Now().UTC().Add(-90 * time.Second), SourceOnline: true},
+		ok:       true,
+		ready:    true,
+	}, testOptions)
+
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil))
+
+	age, err := strconv.ParseInt(rr.Header().Get("X-Snapshot-Age-Seconds"), 10, 64)
+	if err != nil || age < 90 || age > 91 {
+		t.Fatalf("unexpected X-Snapshot-Age-Seconds: %q", rr.Header().Get("X-Snapshot-Age-Seconds"))
+	}
+
+	now := time.Now()
+	if got := snapshotAgeSeconds(now.Add(time.Second), now); got != 0 {
+		t.Fatalf("expected a snapshot from the future to report age 0, got %d", got)
+	}
+}
+
+func TestDashboardEndpointSince(t *testing.T) {
+	generatedAt := time.Date(2026, 2, 6, 10, 0, 0, 500, time.UTC)
+	api := New(fakeReader{
+		snapshot: model.DashboardSnapshot{GeneratedAt: generatedAt, SourceOnline: true},
+		ok:       true,
+		ready:    true,
+	}, testOptions)
+
+	cases := []struct {
+		name  string
+		since string
+		want  int
+	}{
+		{"older", generatedAt.Add(-time.Second).Format(time.RFC3339), http.StatusOK},
+		{"equal", generatedAt.Format(time.RFC3339Nano), http.StatusNotModified},
+		{"newer", generatedAt.Add(time.Second).Format(time.RFC3339), http.StatusNotModified},
+		{"other zone", generatedAt.In(time.FixedZone("BRT", -3*3600)).Format(time.RFC3339Nano), http.StatusNotModified},
+		{"invalid", "yesterday", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard?since="+url.QueryEscape(tc.since), nil)
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, req)
+		if rr.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d: %s", tc.name, tc.want, rr.Code, rr.Body.String())
+		}
+		if tc.want == http.StatusNotModified && rr.Body.Len() != 0 {
+			t.Fatalf("%s: expected empty body on 304, got %q", tc.name, rr.Body.String())
+		}
+	}
+}
+
+func TestDashboardEndpointConditionalGet(t *testing.T) {
+	reader := fakeReader{
+		snapshot: model.DashboardSnapshot{
+			GeneratedAt:  time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC),
+			SourceOnline: true,
+		},
+		ok:    true,
+		ready: true,
+	}
+	api := New(reader, testOptions)
+
+	first := httptest.NewRecorder()
+	api.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil))
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("expected ETag header")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil)
+	req.Header.Set("If-None-Match", etag)
+	matching := httptest.NewRecorder()
+	api.ServeHTTP(matching, req)
+	if matching.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for matching If-None-Match, got %d", matching.Code)
+	}
+	if matching.Body.Len() != 0 {
+		t.Fatalf("expected empty body on 304, got %q", matching.Body.String())
+	}
+
+	reader.snapshot.GeneratedAt = reader.snapshot.GeneratedAt.Add(5 * time.Second)
+	api = New(reader, testOptions)
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil)
+	req.Header.Set("If-None-Match", etag)
+	stale := httptest.NewRecorder()
+	api.ServeHTTP(stale, req)
+	if stale.Code != http.StatusOK {
+		t.Fatalf("expected 200 for stale If-None-Match, got %d", stale.Code)
+	}
+	if stale.Header().Get("ETag") == etag {
+		t.Fatalf("expected ETag to change with the snapshot")
+	}
 }
 
 func TestDashboardEndpointMethodNotAllowed(t *testing.T) {
-	api := New(fakeReader{ok: true, ready: true}, "Syncthing", "Read-Only Dashboard", 5*time.Second)
+	api := New(fakeReader{ok: true, ready: true}, testOptions)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/dashboard", nil)
 	rr := httptest.NewRecorder()
@@ -76,11 +255,152 @@ func TestDashboardEndpointMethodNotAllowed(t *testing.T) {
 	if rr.Code != http.StatusMethodNotAllowed {
 		t.Fatalf("expected 405, got %d", rr.Code)
 	}
+	if code := errorCode(t, rr); code != "METHOD_NOT_ALLOWED" {
+		t.Fatalf("expected METHOD_NOT_ALLOWED, got %q", code)
+	}
+}
+
+// errorCode decodes the code from a JSON error response.
+func errorCode(t *testing.T, rr *httptest.ResponseRecorder) string {
+	t.Helper()
+	var payload errorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode error response %q: %v", rr.Body.String(), err)
+	}
+	if payload.Message == "" {
+		t.Fatalf("expected an error message, got %q", rr.Body.String())
+	}
+	return payload.Code
+}
+
+func TestAlertsEndpointSortsAndFilters(t *testing.T) {
+	generatedAt := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
+	api := New(fakeReader{
+		snapshot: model.DashboardSnapshot{
+			GeneratedAt: generatedAt,
+			Alerts: []model.Alert{
+				{Severity: "warn", Code: "FOLDER_OUT_OF_SYNC", SubjectID: "media"},
+				{Severity: "info", Code: "CONFIG_EMPTY"},
+				{Severity: "critical", Code: "REMOTE_DISCONNECTED", SubjectID: "keyring"},
+				{Severity: "critical", Code: "FOLDER_ERROR", SubjectID: "videos"},
+				{Severity: "warn", Code: "FOLDER_OUT_OF_SYNC", SubjectID: "music"},
+			},
+		},
+		ok:    true,
+		ready: true,
+	}, testOptions)
+
+	decode := func(target string) alertsResponse {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200 for %s, got %d", target, rr.Code)
+		}
+		var payload alertsResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		return payload
+	}
+
+	all := decode("/api/v1/alerts")
+	if all.Count != 5 || len(all.Alerts) != 5 || !all.GeneratedAt.Equal(generatedAt) {
+		t.Fatalf("unexpected unfiltered payload: %+v", all)
+	}
+	order := []string{"FOLDER_ERROR", "REMOTE_DISCONNECTED", "FOLDER_OUT_OF_SYNC", "FOLDER_OUT_OF_SYNC", "CONFIG_EMPTY"}
+	for i, code := range order {
+		if all.Alerts[i].Code != code {
+			t.Fatalf("unexpected order at %d: got %s, want %s", i, all.Alerts[i].Code, code)
+		}
+	}
+	if all.Alerts[2].SubjectID != "media" || all.Alerts[3].SubjectID != "music" {
+		t.Fatalf("expected sort to be stable for equal keys")
+	}
+
+	critical := decode("/api/v1/alerts?severity=critical")
+	if critical.Count != 2 {
+		t.Fatalf("expected 2 critical alerts, got %d", critical.Count)
+	}
+
+	byCode := decode("/api/v1/alerts?code=REMOTE_DISCONNECTED")
+	if byCode.Count != 1 || byCode.Alerts[0].SubjectID != "keyring" {
+		t.Fatalf("unexpected code-filtered payload: %+v", byCode)
+	}
+
+	none := decode("/api/v1/alerts?severity=warn&code=FOLDER_ERROR")
+	if none.Count != 0 || none.Alerts == nil {
+		t.Fatalf("expected empty (non-null) alert list, got %+v", none)
+	}
+}
+
+func TestAlertsEndpointUnavailable(t *testing.T) {
+	api := New(fakeReader{}, testOptions)
+
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/alerts", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rr.Code)
+	}
+	if code := errorCode(t, rr); code != "SNAPSHOT_UNAVAILABLE" {
+		t.Fatalf("expected SNAPSHOT_UNAVAILABLE, got %q", code)
+	}
+}
+
+func TestFoldersCSVEndpoint(t *testing.T) {
+	completion := 42.5
+	lastScan := time.Date(2026, 2, 6, 9, 30, 0, 0, time.UTC)
+	api := New(fakeReader{
+		snapshot: model.DashboardSnapshot{
+			Folders: []model.FolderStatus{
+				{ID: "docs", Label: "Docs, Shared", Path: "/sync/docs", State: "syncing", GlobalFiles: 10, LocalFiles: 8, GlobalBytes: 4096, LocalBytes: 2048, NeedBytes: 2048, CompletionPct: &completion, LastScanAt: &lastScan},
+				{ID: "new", Label: "New", Path: "/sync/new", State: "unknown"},
+			},
+		},
+		ok:    true,
+		ready: true,
+	}, testOptions)
+
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/folders.csv", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("unexpected content type: %q", rr.Header().Get("Content-Type"))
+	}
+	if rr.Header().Get("Content-Disposition") != "attachment; filename=folders.csv" {
+		t.Fatalf("unexpected content disposition: %q", rr.Header().Get("Content-Disposition"))
+	}
+
+	expected := "id,label,path,state,global_files,local_files,global_bytes,local_bytes,need_bytes,completion_pct,last_scan_at\n" +
+		"docs,\"Docs, Shared\",/sync/docs,syncing,10,8,4096,2048,2048,42.5,2026-02-06T09:30:00Z\n" +
+		"new,New,/sync/new,unknown,0,0,0,0,0,,\n"
+	if rr.Body.String() != expected {
+		t.Fatalf("unexpected CSV body:\n%s", rr.Body.String())
+	}
+}
+
+func TestFoldersCSVEndpointUnavailable(t *testing.T) {
+	api := New(fakeReader{}, testOptions)
+
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/folders.csv", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/folders.csv", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rr.Code)
+	}
 }
 
 func TestReadyz(t *testing.T) {
-	readyAPI := New(fakeReader{ok: true, ready: true}, "Syncthing", "Read-Only Dashboard", 5*time.Second)
-	notReadyAPI := New(fakeReader{ok: false, ready: false}, "Syncthing", "Read-Only Dashboard", 5*time.Second)
+	readyAPI := New(fakeReader{ok: true, ready: true}, testOptions)
+	notReadyAPI := New(fakeReader{ok: false, ready: false}, testOptions)
 
 	r1 := httptest.NewRecorder()
 	readyAPI.ServeHTTP(r1, httptest.NewRequest(http.MethodGet, "/readyz", nil))
@@ -96,7 +416,7 @@ func TestReadyz(t *testing.T) {
 }
 
 func TestRootServesIndexHTML(t *testing.T) {
-	api := New(fakeReader{ok: true, ready: true}, "Syncthing", "Read-Only Dashboard", 5*time.Second)
+	api := New(fakeReader{ok: true, ready: true}, testOptions)
 
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	rr := httptest.NewRecorder()
@@ -113,6 +433,30 @@ func TestRootServesIndexHTML(t *testing.T) {
 	}
 }
 
+func TestStaticAssetsServeWithContentTypes(t *testing.T) {
+	api := New(fakeReader{ok: true, ready: true}, testOptions)
+
+	cases := map[string]string{
+		"/favicon.ico": "image/svg+xml",
+		"/favicon.svg": "image/svg+xml",
+		"/app.js":      "text/javascript",
+		"/styles.css":  "text/css",
+	}
+	for path, wantType := range cases {
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, rr.Code)
+		}
+		if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, wantType) {
+			t.Fatalf("%s: expected content type %q, got %q", path, wantType, ct)
+		}
+		if rr.Body.Len() == 0 {
+			t.Fatalf("%s: expected a body", path)
+		}
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsStr(s, substr))
 }
@@ -125,3 +469,798 @@ func containsStr(s, substr string) bool {
 	}
 	return false
 }
+
+func TestHistoryEndpointHonoursLimit(t *testing.T) {
+	base := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
+	ring := history.NewRing(10)
+	for i := 0; i < 5; i++ {
+		ring.Add(history.Entry{GeneratedAt: base.Add(time.Duration(i) * time.Second), SourceOnline: true})
+	}
+	api := New(fakeReader{history: ring.Latest(0)}, testOptions)
+
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/history?limit=3", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+
+	var entries []history.Entry
+	if err := json.Unmarshal(rr.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	for i := 1; i < len(entries); i++ {
+		if !entries[i].GeneratedAt.Before(entries[i-1].GeneratedAt) {
+			t.Fatalf("expected entries in descending time order, got %v", entries)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/history?limit=abc", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid limit, got %d", rr.Code)
+	}
+	if code := errorCode(t, rr); code != "INVALID_PARAMETER" {
+		t.Fatalf("expected INVALID_PARAMETER, got %q", code)
+	}
+}
+
+func TestDashboardEndpointReportsTimezone(t *testing.T) {
+	reader := fakeReader{snapshot: model.DashboardSnapshot{GeneratedAt: time.Now().UTC()}, ok: true, ready: true}
+	opts := testOptions
+	opts.Timezone = "America/Sao_Paulo"
+
+	for _, tc := range []struct {
+		api  *API
+		want string
+	}{
+		{New(reader, opts), "America/Sao_Paulo"},
+		{New(reader, testOptions), ""},
+	} {
+		rr := httptest.NewRecorder()
+		tc.api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil))
+
+		var payload map[string]any
+		if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		got, _ := payload["timezone"].(string)
+		if got != tc.want {
+			t.Fatalf("expected timezone %q, got %q", tc.want, got)
+		}
+	}
+}
+
+func TestStatusTextSummarizesSnapshot(t *testing.T) {
+	api := New(fakeReader{
+		snapshot: model.DashboardSnapshot{
+			GeneratedAt:  time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC),
+			SourceOnline: true,
+			Device:       model.DeviceStatus{DownloadBPS: 0, UploadBPS: 1.2 * 1024 * 1024},
+			Folders: []model.FolderStatus{
+				{ID: "a", State: "idle"},
+				{ID: "b", State: "syncing"},
+				{ID: "c", State: "error"},
+			},
+			Remotes: []model.RemoteDeviceStatus{
+				{ID: "r1", Connected: true},
+				{ID: "r2", Connected: false},
+			},
+		},
+		ok:    true,
+		ready: true,
+	}, testOptions)
+
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/status.txt", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("expected text/plain, got %q", ct)
+	}
+	want := "online=true stale=false folders=3 syncing=1 errors=1 remotes=1/2 down=0B/s up=1.2MiB/s\n"
+	if rr.Body.String() != want {
+		t.Fatalf("unexpected status line:\n got %q\nwant %q", rr.Body.String(), want)
+	}
+
+	rr = httptest.NewRecorder()
+	New(fakeReader{}, testOptions).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/status.txt", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a snapshot, got %d", rr.Code)
+	}
+}
+
+func TestSummaryEndpointCountsSnapshot(t *testing.T) {
+	api := New(fakeReader{
+		snapshot: model.DashboardSnapshot{
+			GeneratedAt:  time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC),
+			SourceOnline: true,
+			Health:       model.HealthDegraded,
+			Device:       model.DeviceStatus{DownloadBPS: 2048, UploadBPS: 512},
+			Folders: []model.FolderStatus{
+				{ID: "a", State: "idle"},
+				{ID: "b", State: "syncing", NeedBytes: 1000},
+				{ID: "c", State: "Syncing", NeedBytes: 500},
+				{ID: "d", State: "error", NeedBytes: 24},
+			},
+			Remotes: []model.RemoteDeviceStatus{
+				{ID: "r1", Connected: true},
+				{ID: "r2", Connected: true},
+				{ID: "r3", Connected: false},
+			},
+		},
+		ok:    true,
+		ready: true,
+	}, testOptions)
+
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/summary", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+
+	var got model.Summary
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode summary: %v", err)
+	}
+	if got.Health != model.HealthDegraded || !got.SourceOnline || got.Stale {
+		t.Fatalf("unexpected status fields: %+v", got)
+	}
+	if got.FoldersTotal != 4 || got.FoldersByState["idle"] != 1 || got.FoldersByState["syncing"] != 2 || got.FoldersByState["error"] != 1 {
+		t.Fatalf("unexpected folder counts: %+v", got)
+	}
+	if got.RemotesConnected != 2 || got.RemotesTotal != 3 {
+		t.Fatalf("unexpected remote counts: %+v", got)
+	}
+	if got.NeedBytes != 1524 || got.DownloadBPS != 2048 || got.UploadBPS != 512 {
+		t.Fatalf("unexpected totals: %+v", got)
+	}
+
+	rr = httptest.NewRecorder()
+	New(fakeReader{}, testOptions).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/summary", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a snapshot, got %d", rr.Code)
+	}
+}
+
+func TestUnknownAPIPathReturnsJSONNotFound(t *testing.T) {
+	api := New(fakeReader{ok: true, ready: true}, testOptions)
+
+	for _, path := range []string{"/api/v1/bogus", "/api/", "/api/v2/dashboard"} {
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404, got %d", path, rr.Code)
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+			t.Fatalf("%s: expected JSON content type, got %q", path, ct)
+		}
+		var payload map[string]string
+		if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil || payload["error"] != "not found" || payload["code"] != "NOT_FOUND" {
+			t.Fatalf("%s: expected not found JSON body, got %q", path, rr.Body.String())
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil))
+	if rr.Code == http.StatusNotFound {
+		t.Fatalf("expected exact API routes to keep working")
+	}
+}
+
+func TestPprofRoutesAreGatedByOption(t *testing.T) {
+	api := New(fakeReader{ok: true, ready: true}, testOptions)
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected pprof to be absent by default, got %d", rr.Code)
+	}
+
+	opts := testOptions
+	opts.EnablePprof = true
+	api = New(fakeReader{ok: true, ready: true}, opts)
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/cmdline", "/debug/pprof/heap"} {
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200 with pprof enabled, got %d", path, rr.Code)
+		}
+	}
+}
+
+func TestSeparateAdminMovesAdminRoutes(t *testing.T) {
+	opts := testOptions
+	opts.EnablePprof = true
+	opts.SeparateAdmin = true
+	api := New(fakeReader{ok: true, ready: true}, opts)
+	admin := api.AdminHandler()
+
+	adminPaths := []string{"/healthz", "/readyz", "/debug/pprof/", "/debug/pprof/cmdline"}
+	for _, path := range adminPaths {
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404 on the main handler, got %d", path, rr.Code)
+		}
+
+		rr = httptest.NewRecorder()
+		admin.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200 on the admin handler, got %d", path, rr.Code)
+		}
+	}
+
+	for _, path := range []string{"/api/v1/dashboard", "/"} {
+		rr := httptest.NewRecorder()
+		admin.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404 on the admin handler, got %d", path, rr.Code)
+		}
+		rr = httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200 on the main handler, got %d", path, rr.Code)
+		}
+	}
+}
+
+func TestRateLimitRejectsBurstWithRetryAfter(t *testing.T) {
+	opts := testOptions
+	opts.RateLimit = 2
+	api := New(fakeReader{ok: true, ready: true}, opts)
+
+	request := func(path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, req)
+		return rr
+	}
+
+	limited := 0
+	for range 10 {
+		rr := request("/api/v1/dashboard", "192.0.2.1:5000")
+		if rr.Code == http.StatusTooManyRequests {
+			limited++
+			if rr.Header().Get("Retry-After") == "" {
+				t.Fatalf("expected Retry-After on 429")
+			}
+		}
+	}
+	if limited != 8 {
+		t.Fatalf("expected 8 of 10 burst requests to be limited, got %d", limited)
+	}
+
+	if rr := request("/api/v1/dashboard", "192.0.2.2:5000"); rr.Code != http.StatusOK {
+		t.Fatalf("expected another client to keep its own budget, got %d", rr.Code)
+	}
+	for _, path := range []string{"/healthz", "/readyz"} {
+		if rr := request(path, "192.0.2.1:5000"); rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected probes to be exempt, got %d", path, rr.Code)
+		}
+	}
+}
+
+func TestMaxConcurrentRejectsWhenSaturated(t *testing.T) {
+	opts := testOptions
+	opts.MaxConcurrent = 2
+	api := New(fakeReader{ok: true, ready: true}, opts)
+
+	request := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	// Hold every slot as two in-flight requests would.
+	for range 2 {
+		if !api.inFlight.acquire() {
+			t.Fatalf("expected a free slot")
+		}
+	}
+	rr := request("/api/v1/dashboard")
+	if rr.Code != http.StatusServiceUnavailable || rr.Header().Get("Retry-After") != "1" {
+		t.Fatalf("expected 503 with Retry-After while saturated, got %d %q", rr.Code, rr.Header().Get("Retry-After"))
+	}
+	if code := errorCode(t, rr); code != codeServerBusy {
+		t.Fatalf("expected %s, got %s", codeServerBusy, code)
+	}
+	for _, path := range []string{"/healthz", "/readyz"} {
+		if rr := request(path); rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected probes to bypass the limit, got %d", path, rr.Code)
+		}
+	}
+
+	api.inFlight.release()
+	if rr := request("/api/v1/dashboard"); rr.Code != http.StatusOK {
+		t.Fatalf("expected a released slot to let the request through, got %d", rr.Code)
+	}
+	if rr := request("/api/v1/dashboard"); rr.Code != http.StatusOK {
+		t.Fatalf("expected the slot to be given back after the request, got %d", rr.Code)
+	}
+}
+
+func TestMaxConcurrentChecksAuthFirst(t *testing.T) {
+	opts := testOptions
+	opts.MaxConcurrent = 1
+	opts.BasicUser, opts.BasicPassword = "viewer", "secret"
+	api := New(fakeReader{ok: true, ready: true}, opts)
+
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without credentials, got %d", rr.Code)
+	}
+	if held := len(api.inFlight.slots); held != 0 {
+		t.Fatalf("expected a rejected request not to take a slot, %d held", held)
+	}
+
+	// With every slot busy, unauthenticated requests are still told 401.
+	if !api.inFlight.acquire() {
+		t.Fatalf("expected a free slot")
+	}
+	rr = httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected auth to be checked before the limit, got %d", rr.Code)
+	}
+	api.inFlight.release()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard", nil)
+	req.SetBasicAuth("viewer", "secret")
+	rr = httptest.NewRecorder()
+	api.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected an authenticated request to be served, got %d", rr.Code)
+	}
+}
+
+func TestSourceHealthReportsOnlineAndOffline(t *testing.T) {
+	generatedAt := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
+	errText := "dial tcp 127.0.0.1:8384: connection refused"
+
+	type payload struct {
+		Online     bool       `json:"online"`
+		LastError  *string    `json:"last_error"`
+		LastGoodAt *time.Time `json:"last_good_at"`
+	}
+	get := func(reader fakeReader) (int, payload) {
+		rr := httptest.NewRecorder()
+		New(reader, testOptions).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/source/health", nil))
+		var body payload
+		if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		return rr.Code, body
+	}
+
+	code, body := get(fakeReader{snapshot: model.DashboardSnapshot{GeneratedAt: generatedAt, SourceOnline: true}, ok: true, ready: true})
+	if code != http.StatusOK || !body.Online || body.LastError != nil || body.LastGoodAt == nil || !body.LastGoodAt.Equal(generatedAt) {
+		t.Fatalf("unexpected online response: %d %+v", code, body)
+	}
+
+	// Offline while serving the last good snapshot.
+	code, body = get(fakeReader{snapshot: model.DashboardSnapshot{GeneratedAt: generatedAt, SourceError: &errText, Stale: true}, ok: true, ready: true})
+	if code != http.StatusServiceUnavailable || body.Online || body.LastError == nil || *body.LastError != errText || body.LastGoodAt == nil {
+		t.Fatalf("unexpected offline response: %d %+v", code, body)
+	}
+
+	// Offline before any poll succeeded.
+	code, body = get(fakeReader{snapshot: model.DashboardSnapshot{GeneratedAt: generatedAt, SourceError: &errText}, ok: true})
+	if code != http.StatusServiceUnavailable || body.LastGoodAt != nil {
+		t.Fatalf("expected no last_good_at before the first good poll, got %d %+v", code, body)
+	}
+}
+
+func TestAccessLogRecordsRequestFields(t *testing.T) {
+	var buf bytes.Buffer
+	opts := testOptions
+	opts.AccessLog = slog.New(slog.NewJSONHandler(&buf, nil))
+	api := New(fakeReader{ok: true, ready: true}, opts)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/bogus", nil)
+	req.RemoteAddr = "192.0.2.7:4000"
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, req)
+	api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one access log line with probes excluded, got %q", buf.String())
+	}
+	var entry struct {
+		Msg      string `json:"msg"`
+		Method   string `json:"method"`
+		Path     string `json:"path"`
+		Status   int    `json:"status"`
+		Bytes    int    `json:"bytes"`
+		Duration int64  `json:"duration"`
+		Remote   string `json:"remote"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("failed to decode log line: %v", err)
+	}
+	if entry.Msg != "http request" || entry.Method != http.MethodGet || entry.Path != "/api/v1/bogus" ||
+		entry.Status != http.StatusNotFound || entry.Bytes != rr.Body.Len() || entry.Duration <= 0 || entry.Remote != "192.0.2.7" {
+		t.Fatalf("unexpected access log entry: %s", lines[0])
+	}
+
+	buf.Reset()
+	opts.AccessLogProbes = true
+	New(fakeReader{ok: true, ready: true}, opts).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if !strings.Contains(buf.String(), `"path":"/healthz"`) {
+		t.Fatalf("expected probes to be logged when enabled, got %q", buf.String())
+	}
+}
+
+func TestRemotesEndpointFiltersByConnection(t *testing.T) {
+	lastSeen := time.Date(2026, 2, 6, 9, 0, 0, 0, time.UTC)
+	api := New(fakeReader{
+		snapshot: model.DashboardSnapshot{
+			GeneratedAt: time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC),
+			Remotes: []model.RemoteDeviceStatus{
+				{ID: "A", Name: "attic", Connected: true},
+				{ID: "B", Name: "backpack", Connected: false, LastSeenAt: &lastSeen},
+				{ID: "C", Name: "cellar", Connected: true},
+			},
+		},
+		ok:    true,
+		ready: true,
+	}, testOptions)
+
+	get := func(query string) (int, remotesResponse) {
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/remotes"+query, nil))
+		var payload remotesResponse
+		if rr.Code == http.StatusOK {
+			if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
+				t.Fatalf("failed to decode payload: %v", err)
+			}
+		}
+		return rr.Code, payload
+	}
+	ids := func(remotes []model.RemoteDeviceStatus) string {
+		var out []string
+		for _, remote := range remotes {
+			out = append(out, remote.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	code, payload := get("")
+	if code != http.StatusOK || payload.Count != 3 || ids(payload.Remotes) != "B,A,C" {
+		t.Fatalf("expected all remotes with disconnected first, got %d %s", code, ids(payload.Remotes))
+	}
+	if payload.Remotes[0].LastSeenAt == nil || !payload.Remotes[0].LastSeenAt.Equal(lastSeen) {
+		t.Fatalf("expected last_seen_at for the disconnected remote")
+	}
+
+	if code, payload = get("?connected=false"); code != http.StatusOK || ids(payload.Remotes) != "B" {
+		t.Fatalf("expected only disconnected remotes, got %d %s", code, ids(payload.Remotes))
+	}
+	if code, payload = get("?connected=true"); code != http.StatusOK || ids(payload.Remotes) != "A,C" || payload.Count != 2 {
+		t.Fatalf("expected only connected remotes, got %d %s", code, ids(payload.Remotes))
+	}
+	if code, _ = get("?connected=maybe"); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid filter, got %d", code)
+	}
+
+	rr := httptest.NewRecorder()
+	New(fakeReader{}, testOptions).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/remotes", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a snapshot, got %d", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/remotes", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for POST, got %d", rr.Code)
+	}
+}
+
+type refreshingReader struct {
+	fakeReader
+	refreshes *atomic.Int32
+}
+
+func (r refreshingReader) RefreshNow(context.Context) error {
+	r.refreshes.Add(1)
+	return nil
+}
+
+func TestRefreshEndpoint(t *testing.T) {
+	reader := refreshingReader{
+		fakeReader: fakeReader{
+			snapshot: model.DashboardSnapshot{GeneratedAt: time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC), SourceOnline: true},
+			ok:       true,
+			ready:    true,
+		},
+		refreshes: &atomic.Int32{},
+	}
+	api := New(reader, testOptions)
+	now := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
+	api.refreshes.now = func() time.Time { return now }
+
+	get := httptest.NewRecorder()
+	api.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/api/v1/refresh", nil))
+	if get.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for GET, got %d", get.Code)
+	}
+
+	first := httptest.NewRecorder()
+	api.ServeHTTP(first, httptest.NewRequest(http.MethodPost, "/api/v1/refresh", nil))
+	if first.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", first.Code, first.Body.String())
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(first.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload["source_online"] != true || payload["page_title"] != "Syncthing" {
+		t.Fatalf("expected dashboard payload, got %v", payload)
+	}
+
+	second := httptest.NewRecorder()
+	api.ServeHTTP(second, httptest.NewRequest(http.MethodPost, "/api/v1/refresh", nil))
+	if second.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 for a refresh within the interval, got %d", second.Code)
+	}
+	if got := second.Header().Get("Retry-After"); got != "5" {
+		t.Fatalf("expected Retry-After 5, got %q", got)
+	}
+	if code := errorCode(t, second); code != "RATE_LIMITED" {
+		t.Fatalf("expected RATE_LIMITED, got %q", code)
+	}
+	if got := reader.refreshes.Load(); got != 1 {
+		t.Fatalf("expected one refresh to reach the collector, got %d", got)
+	}
+
+	now = now.Add(refreshInterval)
+	third := httptest.NewRecorder()
+	api.ServeHTTP(third, httptest.NewRequest(http.MethodPost, "/api/v1/refresh", nil))
+	if third.Code != http.StatusOK || reader.refreshes.Load() != 2 {
+		t.Fatalf("expected refresh to be allowed after the interval, got %d", third.Code)
+	}
+}
+
+func TestRefreshEndpointWithoutRefresher(t *testing.T) {
+	api := New(fakeReader{ok: true, ready: true}, testOptions)
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/refresh", nil))
+	if rr.Code != http.StatusNotImplemented {
+		t.Fatalf("expected 501, got %d", rr.Code)
+	}
+	if code := errorCode(t, rr); code != "NOT_IMPLEMENTED" {
+		t.Fatalf("expected NOT_IMPLEMENTED, got %q", code)
+	}
+}
+
+// liveReader serves whichever snapshot was stored last and wakes waiters on
+// each store, as the collectors do on publish.
+type liveReader struct {
+	fakeReader
+	current *atomic.Pointer[model.DashboardSnapshot]
+	updates *notify.Broadcaster
+}
+
+func newLiveReader(snapshot model.DashboardSnapshot) liveReader {
+	r := liveReader{fakeReader: fakeReader{ready: true}, current: &atomic.Pointer[model.DashboardSnapshot]{}, updates: &notify.Broadcaster{}}
+	r.current.Store(&snapshot)
+	return r
+}
+
+func (r liveReader) Snapshot() (model.DashboardSnapshot, bool) {
+	return *r.current.Load(), true
+}
+
+func (r liveReader) Updated() <-chan struct{} {
+	return r.updates.Wait()
+}
+
+func (r liveReader) store(snapshot model.DashboardSnapshot) {
+	r.current.Store(&snapshot)
+	r.updates.Publish()
+}
+
+func TestDashboardLongPoll(t *testing.T) {
+	reader := newLiveReader(model.DashboardSnapshot{SnapshotID: "aaaaaaaaaaaa", SourceOnline: true})
+	opts := testOptions
+	opts.LongPollTimeout = 300 * time.Millisecond
+	api := New(reader, opts)
+
+	poll := func(query string) (*httptest.ResponseRecorder, time.Duration) {
+		started := time.Now()
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard?"+query, nil))
+		return rr, time.Since(started)
+	}
+	snapshotID := func(rr *httptest.ResponseRecorder) string {
+		t.Helper()
+		var payload dashboardResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return payload.SnapshotID
+	}
+
+	// Nothing new arrives: the current snapshot comes back after the timeout.
+	rr, took := poll("wait=true&since=aaaaaaaaaaaa")
+	if rr.Code != http.StatusOK || snapshotID(rr) != "aaaaaaaaaaaa" {
+		t.Fatalf("expected 200 with the current snapshot on timeout, got %d %s", rr.Code, rr.Body.String())
+	}
+	if took < opts.LongPollTimeout {
+		t.Fatalf("expected the request to be held for the timeout, returned after %s", took)
+	}
+
+	// A client behind the current snapshot is answered at once.
+	if rr, took := poll("wait=true&since=000000000000"); snapshotID(rr) != "aaaaaaaaaaaa" || took >= opts.LongPollTimeout {
+		t.Fatalf("expected an immediate answer for a stale since, got %q after %s", snapshotID(rr), took)
+	}
+
+	// A new snapshot releases the waiting request.
+	opts.LongPollTimeout = 5 * time.Second
+	api = New(reader, opts)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		reader.store(model.DashboardSnapshot{SnapshotID: "bbbbbbbbbbbb", SourceOnline: true})
+	}()
+	rr, took = poll("wait=true&since=aaaaaaaaaaaa")
+	if rr.Code != http.StatusOK || snapshotID(rr) != "bbbbbbbbbbbb" {
+		t.Fatalf("expected the new snapshot, got %d %s", rr.Code, rr.Body.String())
+	}
+	if took >= opts.LongPollTimeout {
+		t.Fatalf("expected the new snapshot to end the wait, took %s", took)
+	}
+
+	if rr, _ := poll("wait=maybe"); rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a bad wait value, got %d", rr.Code)
+	}
+	// A generated_at timestamp is the plain-poll form of since.
+	if rr, _ := poll("wait=true&since=2026-02-06T10:00:00Z"); rr.Code != http.StatusBadRequest || errorCode(t, rr) != codeInvalidParameter {
+		t.Fatalf("expected 400 for a timestamp since while waiting, got %d", rr.Code)
+	}
+}
+
+func TestDashboardLongPollEndsOnClose(t *testing.T) {
+	api := New(fakeReader{snapshot: model.DashboardSnapshot{SnapshotID: "aaaaaaaaaaaa"}, ok: true, ready: true}, testOptions)
+	time.AfterFunc(100*time.Millisecond, api.Close)
+
+	started := time.Now()
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard?wait=true&since=aaaaaaaaaaaa", nil))
+	if took := time.Since(started); took >= defaultLongPollTimeout {
+		t.Fatalf("expected Close to end the wait, took %s", took)
+	}
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected the current snapshot on close, got %d", rr.Code)
+	}
+}
+
+func TestDashboardLongPollStopsWhenClientLeaves(t *testing.T) {
+	api := New(fakeReader{snapshot: model.DashboardSnapshot{SnapshotID: "aaaaaaaaaaaa"}, ok: true, ready: true}, testOptions)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	started := time.Now()
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard?wait=true&since=aaaaaaaaaaaa", nil).WithContext(ctx))
+	if took := time.Since(started); took >= defaultLongPollTimeout {
+		t.Fatalf("expected the wait to end with the request context, took %s", took)
+	}
+	if rr.Body.Len() != 0 {
+		t.Fatalf("expected nothing written to a departed client, got %s", rr.Body.String())
+	}
+}
+
+func TestHeadMatchesGetWithoutBody(t *testing.T) {
+	reader := fakeReader{
+		snapshot: model.DashboardSnapshot{GeneratedAt: time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC), SourceOnline: true},
+		ok:       true,
+		ready:    true,
+	}
+	for _, path := range []string{"/api/v1/dashboard", "/healthz", "/readyz"} {
+		api := New(reader, testOptions)
+		get := httptest.NewRecorder()
+		api.ServeHTTP(get, httptest.NewRequest(http.MethodGet, path, nil))
+		head := httptest.NewRecorder()
+		api.ServeHTTP(head, httptest.NewRequest(http.MethodHead, path, nil))
+
+		if head.Code != http.StatusOK || head.Code != get.Code {
+			t.Fatalf("%s: expected HEAD status %d like GET, got %d", path, get.Code, head.Code)
+		}
+		if head.Body.Len() != 0 {
+			t.Fatalf("%s: expected an empty HEAD body, got %q", path, head.Body.String())
+		}
+		for _, name := range []string{"Content-Type", "Content-Length", "ETag", "X-Snapshot-Generated-At"} {
+			if got, want := head.Header().Get(name), get.Header().Get(name); got != want {
+				t.Fatalf("%s: expected HEAD %s %q like GET, got %q", path, name, want, got)
+			}
+		}
+	}
+
+	api := New(fakeReader{}, testOptions)
+	for _, path := range []string{"/api/v1/dashboard", "/readyz"} {
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodHead, path, nil))
+		if rr.Code != http.StatusServiceUnavailable || rr.Body.Len() != 0 {
+			t.Fatalf("%s: expected a bodyless 503 without a snapshot, got %d %q", path, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestByteCountsAsStrings(t *testing.T) {
+	const big = model.ByteCount(1)<<53 + 1
+	reader := fakeReader{
+		snapshot: model.DashboardSnapshot{
+			SourceOnline: true,
+			Device:       model.DeviceStatus{SessionInBytes: big, LocalFilesTotal: 12},
+			Folders:      []model.FolderStatus{{ID: "media", GlobalBytes: big}},
+		},
+		ok:    true,
+		ready: true,
+	}
+	api := New(reader, testOptions)
+	get := func(path string) string {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", path, rr.Code)
+		}
+		return rr.Body.String()
+	}
+
+	plain := get("/api/v1/dashboard")
+	if !strings.Contains(plain, `"session_in_bytes":9007199254740993,"session_out_bytes":0}`) {
+		t.Fatalf("expected byte counts as JSON numbers by default, got %s", plain)
+	}
+
+	model.SetByteCountsAsStrings(true)
+	t.Cleanup(func() { model.SetByteCountsAsStrings(false) })
+	quoted := get("/api/v1/dashboard")
+	// Only the byte counts change; every other field and the key order stay.
+	if want := strings.ReplaceAll(plain, ":9007199254740993,", `:"9007199254740993",`); strings.ReplaceAll(quoted, `"0"`, "0") != want {
+		t.Fatalf("expected only byte counts quoted:\n%s\n%s", plain, quoted)
+	}
+	if !strings.Contains(quoted, `"local_files_total":12,`) {
+		t.Fatalf("expected non-byte counts to stay numbers, got %s", quoted)
+	}
+	if summary := get("/api/v1/summary"); !strings.Contains(summary, `"need_bytes":"0"`) {
+		t.Fatalf("expected summary byte counts as strings, got %s", summary)
+	}
+}
+
+func TestConfigEndpoint(t *testing.T) {
+	api := New(fakeReader{ok: true, ready: true}, testOptions)
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/config", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a configuration, got %d", rr.Code)
+	}
+
+	opts := testOptions
+	opts.EffectiveConfig = map[string]any{"STAPIKey": "***", "PollInterval": "5s"}
+	opts.BasicUser = "viewer"
+	opts.BasicPassword = "secret"
+	api = New(fakeReader{ok: true, ready: true}, opts)
+
+	rr = httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/config", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected basic auth to cover the config endpoint, got %d", rr.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/config", nil)
+	req.SetBasicAuth("viewer", "secret")
+	rr = httptest.NewRecorder()
+	api.ServeHTTP(rr, req)
+	var payload map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if rr.Code != http.StatusOK || payload["STAPIKey"] != "***" || payload["PollInterval"] != "5s" {
+		t.Fatalf("expected the configuration as given, got %d %v", rr.Code, payload)
+	}
+}
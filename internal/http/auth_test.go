@@ -0,0 +1,57 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"syncthing-dashboard/internal/model"
+)
+
+func TestBasicAuth(t *testing.T) {
+	opts := testOptions
+	opts.BasicUser = "admin"
+	opts.BasicPassword = "s3cret"
+	api := New(fakeReader{
+		snapshot: model.DashboardSnapshot{GeneratedAt: time.Now().UTC(), SourceOnline: true},
+		ok:       true,
+		ready:    true,
+	}, opts)
+
+	cases := []struct {
+		name     string
+		path     string
+		user     string
+		password string
+		noAuth   bool
+		want     int
+	}{
+		{name: "correct", path: "/api/v1/dashboard", user: "admin", password: "s3cret", want: http.StatusOK},
+		{name: "wrong password", path: "/api/v1/dashboard", user: "admin", password: "guess", want: http.StatusUnauthorized},
+		{name: "wrong user", path: "/api/v1/dashboard", user: "root", password: "s3cret", want: http.StatusUnauthorized},
+		{name: "missing", path: "/api/v1/dashboard", noAuth: true, want: http.StatusUnauthorized},
+		{name: "missing on UI", path: "/", noAuth: true, want: http.StatusUnauthorized},
+		{name: "liveness probe", path: "/healthz", noAuth: true, want: http.StatusOK},
+		{name: "readiness probe", path: "/readyz", noAuth: true, want: http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if !tc.noAuth {
+			req.SetBasicAuth(tc.user, tc.password)
+		}
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, req)
+
+		if rr.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.want, rr.Code)
+		}
+		challenge := rr.Header().Get("WWW-Authenticate")
+		if tc.want == http.StatusUnauthorized && challenge == "" {
+			t.Fatalf("%s: expected a WWW-Authenticate challenge", tc.name)
+		}
+		if tc.want != http.StatusUnauthorized && challenge != "" {
+			t.Fatalf("%s: unexpected challenge %q", tc.name, challenge)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// concurrencyLimiter caps how many requests are served at once. It is a
+// counting semaphore: a slot is taken for the whole request and given back
+// when the handler returns.
+type concurrencyLimiter struct {
+	slots chan struct{}
+}
+
+func newConcurrencyLimiter(limit int) *concurrencyLimiter {
+	return &concurrencyLimiter{slots: make(chan struct{}, limit)}
+}
+
+// acquire takes a slot without waiting, reporting false when all are in use.
+func (l *concurrencyLimiter) acquire() bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *concurrencyLimiter) release() {
+	<-l.slots
+}
+
+// isStreamingRequest reports whether r is a WebSocket stream or a long-poll.
+// Both stay open for minutes, so holding a slot for them would let a few open
+// tabs starve every other request; they are left out of the limit.
+func isStreamingRequest(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/api/v1/dashboard/ws":
+		return true
+	case "/api/v1/dashboard":
+		wait, _ := strconv.ParseBool(r.URL.Query().Get("wait"))
+		return wait
+	}
+	return false
+}
+
+// serverBusy answers 503 with Retry-After when no slot is free. Slots free up
+// as soon as in-flight requests finish, so clients may retry right away.
+func serverBusy(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", "1")
+	writeError(w, http.StatusServiceUnavailable, codeServerBusy, "too many concurrent requests")
+}
@@ -0,0 +1,112 @@
+package httpapi
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRateLimitClients bounds how many per-IP buckets are tracked at once.
+	maxRateLimitClients = 10000
+	// rateLimitSweepInterval is how often buckets idle long enough to have
+	// refilled completely are dropped.
+	rateLimitSweepInterval = time.Minute
+)
+
+// rateLimiter is a per-client-IP token bucket. Each bucket holds up to one
+// second's worth of requests and refills continuously at rate per second.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   max(1, rate),
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// allow takes a token from key's bucket. When none is left it reports how
+// long until the next one is available.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= rateLimitSweepInterval {
+		l.sweep(now)
+	}
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		if len(l.buckets) >= maxRateLimitClients {
+			l.sweep(now)
+		}
+		// Past the bound new clients go untracked rather than evicting
+		// someone else's partially drained bucket.
+		if len(l.buckets) < maxRateLimitClients {
+			l.buckets[key] = bucket
+		}
+	}
+
+	bucket.tokens = min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep drops buckets that would be full by now; recreating them later is
+// indistinguishable from keeping them. Must be called with l.mu held.
+func (l *rateLimiter) sweep(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// limit answers 429 with Retry-After once the client IP has run out of tokens.
+func (l *rateLimiter) limit(w http.ResponseWriter, r *http.Request) bool {
+	ok, retryAfter := l.allow(clientIP(r))
+	if ok {
+		return true
+	}
+	tooManyRequests(w, retryAfter)
+	return false
+}
+
+func tooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	writeError(w, http.StatusTooManyRequests, codeRateLimited, "rate limit exceeded")
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
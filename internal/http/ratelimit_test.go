@@ -0,0 +1,35 @@
+package httpapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterRefillsAndSweepsIdleBuckets(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newRateLimiter(1)
+	l.now = func() time.Time { return now }
+
+	if ok, _ := l.allow("a"); !ok {
+		t.Fatalf("expected first request to be allowed")
+	}
+	ok, retryAfter := l.allow("a")
+	if ok || retryAfter <= 0 || retryAfter > time.Second {
+		t.Fatalf("expected second request to wait up to a second, got %v %v", ok, retryAfter)
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := l.allow("a"); !ok {
+		t.Fatalf("expected a refilled token after one second")
+	}
+
+	_, _ = l.allow("b")
+	now = now.Add(2 * rateLimitSweepInterval)
+	_, _ = l.allow("c")
+	if _, tracked := l.buckets["a"]; tracked {
+		t.Fatalf("expected idle bucket to be swept")
+	}
+	if len(l.buckets) != 1 {
+		t.Fatalf("expected only the active bucket to remain, got %d", len(l.buckets))
+	}
+}
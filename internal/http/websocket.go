@@ -0,0 +1,279 @@
+package httpapi
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// websocketGUID is appended to the client key to derive Sec-WebSocket-Accept,
+// per RFC 6455 section 1.3.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes used by the dashboard stream.
+const (
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+	wsOpPing  = 0x9
+	wsOpPong  = 0xA
+)
+
+// Client frames are only control frames and short messages the stream
+// ignores, so anything larger is treated as a protocol error.
+const wsMaxClientPayload = 4 << 10
+
+// The stream pings the client every wsPingInterval. A client that misses two
+// pings in a row is dropped, as is one that cannot take a frame within
+// wsWriteTimeout.
+const (
+	wsPingInterval = 30 * time.Second
+	wsWriteTimeout = 10 * time.Second
+)
+
+// handleDashboardWS streams the dashboard payload over a WebSocket, sending
+// the current snapshot on connect and, each time the reader publishes one,
+// the new snapshot if its SnapshotID changed. It is for browsers behind
+// proxies that break long polling but pass WebSockets.
+func (a *API) handleDashboardWS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	key := strings.TrimSpace(r.Header.Get("Sec-WebSocket-Key"))
+	if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" || key == "" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		writeError(w, http.StatusUpgradeRequired, codeUpgradeRequired, "websocket upgrade required")
+		return
+	}
+	// Browsers send cached Basic credentials with cross-site WebSocket
+	// handshakes, and the same-origin policy does not apply to them, so any
+	// page could otherwise read the stream.
+	if !sameOrigin(r) {
+		writeError(w, http.StatusForbidden, codeForbidden, "cross-origin websocket not allowed")
+		return
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, "websocket not supported on this connection")
+		return
+	}
+	defer conn.Close()
+	// Deadlines set by the server for the HTTP request no longer apply.
+	_ = conn.SetDeadline(time.Time{})
+
+	ws := &wsConn{conn: conn, rw: rw}
+	ws.lastPong.Store(time.Now().UnixNano())
+	if err := ws.handshake(key); err != nil {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ws.readLoop()
+	}()
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	// The offline fallback keeps the last good GeneratedAt, so changes are
+	// told apart by SnapshotID, as long-polling does.
+	sent := false
+	var lastSent string
+	send := func() error {
+		snapshot, ok := a.reader.Snapshot()
+		if !ok || (sent && snapshot.SnapshotID == lastSent) {
+			return nil
+		}
+		body, err := json.Marshal(a.dashboardResponse(snapshot))
+		if err != nil {
+			return err
+		}
+		sent, lastSent = true, snapshot.SnapshotID
+		return ws.writeFrame(wsOpText, body)
+	}
+
+	updated := a.reader.Updated()
+	if err := send(); err != nil {
+		return
+	}
+	for {
+		select {
+		case <-a.closing:
+			_ = ws.writeFrame(wsOpClose, closePayload(1001))
+			return
+		case <-done:
+			return
+		case <-updated:
+			updated = a.reader.Updated()
+			if err := send(); err != nil {
+				return
+			}
+		case <-ping.C:
+			if time.Since(time.Unix(0, ws.lastPong.Load())) > 2*wsPingInterval {
+				_ = ws.writeFrame(wsOpClose, closePayload(1001))
+				return
+			}
+			if err := ws.writeFrame(wsOpPing, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sameOrigin reports whether r has no Origin header, as with non-browser
+// clients, or one whose host matches the request's Host.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.Host, r.Host)
+}
+
+// wsConn is the server side of one WebSocket connection. Writes come from
+// both the stream loop and the read loop's pong replies, so they are
+// serialized.
+type wsConn struct {
+	conn     net.Conn
+	rw       *bufio.ReadWriter
+	writeMu  sync.Mutex
+	lastPong atomic.Int64
+}
+
+func (ws *wsConn) handshake(key string) error {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+	_, _ = ws.rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	return ws.rw.Flush()
+}
+
+// writeFrame sends one unmasked, unfragmented frame.
+func (ws *wsConn) writeFrame(opcode byte, payload []byte) error {
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+
+	header := []byte{0x80 | opcode, 0}
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	_ = ws.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	_, _ = ws.rw.Write(header)
+	_, _ = ws.rw.Write(payload)
+	return ws.rw.Flush()
+}
+
+// readLoop answers pings, records pongs and returns once the client closes
+// the connection or breaks the protocol.
+func (ws *wsConn) readLoop() {
+	for {
+		opcode, payload, err := readClientFrame(ws.rw.Reader)
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case wsOpClose:
+			_ = ws.writeFrame(wsOpClose, payload[:min(2, len(payload))])
+			return
+		case wsOpPing:
+			if ws.writeFrame(wsOpPong, payload) != nil {
+				return
+			}
+		case wsOpPong:
+			ws.lastPong.Store(time.Now().UnixNano())
+		}
+	}
+}
+
+var errBadFrame = errors.New("websocket: bad client frame")
+
+// readClientFrame reads one masked client frame and returns its unmasked
+// payload. Fragmented messages are not expected from dashboard clients.
+func readClientFrame(r *bufio.Reader) (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := head[0] & 0x0F
+	if head[1]&0x80 == 0 {
+		return 0, nil, errBadFrame
+	}
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > wsMaxClientPayload {
+		return 0, nil, errBadFrame
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
+
+func closePayload(code uint16) []byte {
+	return binary.BigEndian.AppendUint16(nil, code)
+}
+
+// headerHasToken reports whether a comma-separated header contains token,
+// ignoring case.
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
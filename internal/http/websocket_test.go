@@ -0,0 +1,253 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"syncthing-dashboard/internal/model"
+)
+
+func TestDashboardWebSocketStreamsSnapshot(t *testing.T) {
+	generatedAt := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
+	api := New(fakeReader{
+		snapshot: model.DashboardSnapshot{GeneratedAt: generatedAt, SourceOnline: true},
+		ok:       true,
+		ready:    true,
+	}, testOptions)
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	conn, reader := dialDashboardWS(t, ts)
+	defer conn.Close()
+
+	opcode, payload := readServerFrame(t, reader)
+	if opcode != wsOpText {
+		t.Fatalf("expected a text frame, got opcode %d", opcode)
+	}
+	var got dashboardResponse
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatalf("decode frame: %v", err)
+	}
+	if !got.GeneratedAt.Equal(generatedAt) || got.PageTitle != "Syncthing" {
+		t.Fatalf("unexpected snapshot frame: %+v", got)
+	}
+
+	// A masked ping from the client is answered with a pong echoing it.
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | wsOpPing, 0x80 | 2, mask[0], mask[1], mask[2], mask[3], 'h' ^ mask[0], 'i' ^ mask[1]}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatalf("write ping: %v", err)
+	}
+	opcode, payload = readServerFrame(t, reader)
+	if opcode != wsOpPong || string(payload) != "hi" {
+		t.Fatalf("expected pong \"hi\", got opcode %d payload %q", opcode, payload)
+	}
+}
+
+func TestDashboardWebSocketPushesOfflineFallback(t *testing.T) {
+	generatedAt := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
+	live := newLiveReader(model.DashboardSnapshot{GeneratedAt: generatedAt, SnapshotID: "good", SourceOnline: true})
+	ts := httptest.NewServer(New(live, testOptions))
+	defer ts.Close()
+
+	conn, reader := dialDashboardWS(t, ts)
+	defer conn.Close()
+
+	decode := func() dashboardResponse {
+		t.Helper()
+		opcode, payload := readServerFrame(t, reader)
+		if opcode != wsOpText {
+			t.Fatalf("expected a text frame, got opcode %d", opcode)
+		}
+		var got dashboardResponse
+		if err := json.Unmarshal(payload, &got); err != nil {
+			t.Fatalf("decode frame: %v", err)
+		}
+		return got
+	}
+	if got := decode(); !got.SourceOnline {
+		t.Fatalf("expected the online snapshot first, got %+v", got)
+	}
+
+	// The collector's fallback keeps the last good GeneratedAt; only its
+	// content, and so its SnapshotID, changes.
+	errText := "connection refused"
+	live.store(model.DashboardSnapshot{GeneratedAt: generatedAt, SnapshotID: "offline", SourceError: &errText, Stale: true})
+	got := decode()
+	if got.SourceOnline || !got.Stale || got.SnapshotID != "offline" {
+		t.Fatalf("expected the offline fallback to be pushed, got %+v", got)
+	}
+}
+
+func TestMaxConcurrentIgnoresOpenStreams(t *testing.T) {
+	opts := testOptions
+	opts.MaxConcurrent = 1
+	opts.LongPollTimeout = 5 * time.Second
+	api := New(fakeReader{snapshot: model.DashboardSnapshot{SnapshotID: "aaaaaaaaaaaa"}, ok: true, ready: true}, opts)
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	conn, _ := dialDashboardWS(t, ts)
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	parked := make(chan struct{})
+	go func() {
+		defer close(parked)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/api/v1/dashboard?wait=true&since=aaaaaaaaaaaa", nil)
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	// Give the long-poll time to reach the handler and park.
+	time.Sleep(100 * time.Millisecond)
+
+	for _, path := range []string{"/api/v1/dashboard", "/"} {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: expected open streams not to use up the limit, got %d", path, resp.StatusCode)
+		}
+	}
+	cancel()
+	<-parked
+}
+
+func TestDashboardWebSocketRejectsCrossOrigin(t *testing.T) {
+	opts := testOptions
+	opts.BasicUser, opts.BasicPassword = "viewer", "secret"
+	api := New(fakeReader{ok: true, ready: true}, opts)
+
+	upgrade := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://dash.lan:8080/api/v1/dashboard/ws", nil)
+		req.SetBasicAuth("viewer", "secret")
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := upgrade("https://evil.example")
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a cross-origin handshake, got %d", rr.Code)
+	}
+	if code := errorCode(t, rr); code != codeForbidden {
+		t.Fatalf("expected %s, got %s", codeForbidden, code)
+	}
+	// A same-origin handshake gets past the check; the recorder cannot be
+	// hijacked, so it stops there with a 500 instead of upgrading.
+	if rr := upgrade("http://dash.lan:8080"); rr.Code == http.StatusForbidden {
+		t.Fatalf("expected a same-origin handshake to be allowed")
+	}
+}
+
+func TestDashboardWebSocketClosesOnShutdown(t *testing.T) {
+	api := New(fakeReader{ok: true, ready: true}, testOptions)
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+	ts.Config.RegisterOnShutdown(api.Close)
+
+	conn, reader := dialDashboardWS(t, ts)
+	defer conn.Close()
+	if opcode, _ := readServerFrame(t, reader); opcode != wsOpText {
+		t.Fatalf("expected the snapshot frame first, got opcode %d", opcode)
+	}
+
+	if err := ts.Config.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	opcode, payload := readServerFrame(t, reader)
+	if opcode != wsOpClose || binary.BigEndian.Uint16(payload) != 1001 {
+		t.Fatalf("expected a going-away close frame on shutdown, got opcode %d payload %v", opcode, payload)
+	}
+}
+
+func TestDashboardWebSocketRequiresUpgrade(t *testing.T) {
+	api := New(fakeReader{ok: true, ready: true}, testOptions)
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/ws", nil))
+	if rr.Code != http.StatusUpgradeRequired {
+		t.Fatalf("expected 426, got %d", rr.Code)
+	}
+	if code := errorCode(t, rr); code != "UPGRADE_REQUIRED" {
+		t.Fatalf("expected UPGRADE_REQUIRED, got %q", code)
+	}
+}
+
+// dialDashboardWS opens the dashboard stream on ts and checks the handshake.
+func dialDashboardWS(t *testing.T, ts *httptest.Server) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/dashboard/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	if err := req.Write(conn); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		t.Fatalf("read handshake: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %d", resp.StatusCode)
+	}
+	// The accept value for this key is the worked example in RFC 6455.
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected Sec-WebSocket-Accept %q", got)
+	}
+	return conn, reader
+}
+
+func readServerFrame(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	t.Helper()
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		t.Fatalf("read frame header: %v", err)
+	}
+	if head[1]&0x80 != 0 {
+		t.Fatalf("server frames must not be masked")
+	}
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		_, _ = io.ReadFull(r, ext[:])
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		_, _ = io.ReadFull(r, ext[:])
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatalf("read frame payload: %v", err)
+	}
+	return head[0] & 0x0F, payload
+}
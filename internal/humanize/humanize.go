@@ -0,0 +1,39 @@
+// Package humanize formats byte counts and transfer rates with binary prefixes,
+// matching the dashboard UI's formatting.
+package humanize
+
+import (
+	"fmt"
+	"math"
+)
+
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// Bytes formats a byte count such as "1.3 GiB". Negative values render as "0 B".
+func Bytes(value int64) string {
+	return formatBytes(float64(value))
+}
+
+// BytesPerSecond formats a transfer rate such as "2.4 MiB/s". Negative and
+// non-finite values render as "0 B/s".
+func BytesPerSecond(value float64) string {
+	return formatBytes(value) + "/s"
+}
+
+func formatBytes(value float64) string {
+	if value < 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		value = 0
+	}
+
+	unit := 0
+	for value >= 1024 && unit < len(byteUnits)-1 {
+		value /= 1024
+		unit++
+	}
+
+	// Whole numbers for plain bytes and three-digit values, one decimal otherwise.
+	if unit == 0 || value >= 100 {
+		return fmt.Sprintf("%.0f %s", value, byteUnits[unit])
+	}
+	return fmt.Sprintf("%.1f %s", value, byteUnits[unit])
+}
@@ -0,0 +1,55 @@
+package humanize
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		value int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1, "1 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{100 * 1024, "100 KiB"},
+		{1395864371, "1.3 GiB"},
+		{910 << 30, "910 GiB"},
+		{3 << 40, "3.0 TiB"},
+		{150 << 40, "150 TiB"},
+		{math.MaxInt64, "8.0 EiB"},
+		{-1, "0 B"},
+		{math.MinInt64, "0 B"},
+	}
+
+	for _, tc := range tests {
+		if got := Bytes(tc.value); got != tc.want {
+			t.Errorf("Bytes(%d) = %q, want %q", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestBytesPerSecond(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{0, "0 B/s"},
+		{512.4, "512 B/s"},
+		{2.4 * 1024 * 1024, "2.4 MiB/s"},
+		{145 * 1024, "145 KiB/s"},
+		{5 * (1 << 40), "5.0 TiB/s"},
+		{-100, "0 B/s"},
+		{math.NaN(), "0 B/s"},
+		{math.Inf(1), "0 B/s"},
+	}
+
+	for _, tc := range tests {
+		if got := BytesPerSecond(tc.value); got != tc.want {
+			t.Errorf("BytesPerSecond(%v) = %q, want %q", tc.value, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// SplitConnAddress splits a Syncthing connection address such as
+// "tcp://10.0.0.5:22000" or "quic://[2001:db8::1]:22000" into scheme, host,
+// and port. Bracketed IPv6 hosts are returned without brackets. When the
+// address cannot be parsed, host holds the raw value.
+func SplitConnAddress(address string) (scheme, host, port string) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return "", "", ""
+	}
+
+	hostPort := address
+	if strings.Contains(address, "://") {
+		parsed, err := url.Parse(address)
+		if err != nil || parsed.Host == "" {
+			return "", address, ""
+		}
+		scheme = parsed.Scheme
+		hostPort = parsed.Host
+	}
+
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return scheme, strings.Trim(hostPort, "[]"), ""
+	}
+	return scheme, host, port
+}
@@ -0,0 +1,29 @@
+package model
+
+import "testing"
+
+func TestSplitConnAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		scheme  string
+		host    string
+		port    string
+	}{
+		{"tcp://10.0.0.5:22000", "tcp", "10.0.0.5", "22000"},
+		{"quic://[2001:db8::1]:22000", "quic", "2001:db8::1", "22000"},
+		{"relay://relay.example.net:22067", "relay", "relay.example.net", "22067"},
+		{"192.168.10.24:22000", "", "192.168.10.24", "22000"},
+		{"[fe80::1]:22000", "", "fe80::1", "22000"},
+		{"tcp://[2001:db8::1]", "tcp", "2001:db8::1", ""},
+		{"not an address", "", "not an address", ""},
+		{"tcp://%zz", "", "tcp://%zz", ""},
+		{"", "", "", ""},
+	}
+
+	for _, tc := range tests {
+		scheme, host, port := SplitConnAddress(tc.address)
+		if scheme != tc.scheme || host != tc.host || port != tc.port {
+			t.Errorf("SplitConnAddress(%q) = (%q, %q, %q), want (%q, %q, %q)", tc.address, scheme, host, port, tc.scheme, tc.host, tc.port)
+		}
+	}
+}
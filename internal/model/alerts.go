@@ -2,9 +2,73 @@ package model
 
 import (
 	"fmt"
+	"path"
+	"slices"
 	"strings"
+
+	"syncthing-dashboard/internal/humanize"
+)
+
+// AlertSeverities lists the alert severities, most severe first.
+var AlertSeverities = []string{"critical", "warn", "info"}
+
+// Numeric severity levels; higher is more severe. Unknown severities get
+// SeverityLevelUnknown and sort below info.
+const (
+	SeverityLevelUnknown  = 0
+	SeverityLevelInfo     = 1
+	SeverityLevelWarn     = 2
+	SeverityLevelCritical = 3
 )
 
+// SeverityLevel maps a severity name, case-insensitively, to its level.
+func SeverityLevel(severity string) int {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return SeverityLevelCritical
+	case "warn":
+		return SeverityLevelWarn
+	case "info":
+		return SeverityLevelInfo
+	default:
+		return SeverityLevelUnknown
+	}
+}
+
+// SuppressAlerts drops alerts whose severity is in severities. Alerts about
+// the Syncthing API itself being unavailable are always kept.
+func SuppressAlerts(alerts []Alert, severities []string) []Alert {
+	if len(severities) == 0 {
+		return alerts
+	}
+	return slices.DeleteFunc(alerts, func(alert Alert) bool {
+		if alert.Code == "SOURCE_UNREACHABLE" || alert.Code == "SOURCE_UNAUTHORIZED" {
+			return false
+		}
+		return slices.Contains(severities, strings.ToLower(alert.Severity))
+	})
+}
+
+// TruncateAlerts keeps at most limit alerts, most severe first, and appends an
+// ALERTS_TRUNCATED info alert counting the rest. Alerts keep their order when
+// under the limit; a limit of zero or less keeps everything.
+func TruncateAlerts(alerts []Alert, limit int) []Alert {
+	if limit <= 0 || len(alerts) <= limit {
+		return alerts
+	}
+	sorted := slices.Clone(alerts)
+	slices.SortStableFunc(sorted, func(a, b Alert) int {
+		return SeverityLevel(b.Severity) - SeverityLevel(a.Severity)
+	})
+	hidden := len(sorted) - limit
+	return append(sorted[:limit], Alert{
+		Severity:  "info",
+		Code:      "ALERTS_TRUNCATED",
+		Message:   fmt.Sprintf("%d more alerts hidden", hidden),
+		SubjectID: "alerts",
+	})
+}
+
 // DeriveAlerts generates alerts from the current remote and folder state.
 func DeriveAlerts(remotes []RemoteDeviceStatus, folders []FolderStatus) []Alert {
 	alerts := make([]Alert, 0)
@@ -13,10 +77,14 @@ func DeriveAlerts(remotes []RemoteDeviceStatus, folders []FolderStatus) []Alert
 		if remote.Connected {
 			continue
 		}
+		message := fmt.Sprintf("Remote device %s is disconnected", remote.Name)
+		if remote.LastError != nil {
+			message = fmt.Sprintf("%s: %s", message, *remote.LastError)
+		}
 		alerts = append(alerts, Alert{
 			Severity:  "critical",
 			Code:      "REMOTE_DISCONNECTED",
-			Message:   fmt.Sprintf("Remote device %s is disconnected", remote.Name),
+			Message:   message,
 			SubjectID: remote.ID,
 		})
 	}
@@ -32,6 +100,48 @@ func DeriveAlerts(remotes []RemoteDeviceStatus, folders []FolderStatus) []Alert
 			continue
 		}
 
+		if strings.EqualFold(folder.State, "pending") {
+			alerts = append(alerts, Alert{
+				Severity:  "info",
+				Code:      "FOLDER_PENDING",
+				Message:   fmt.Sprintf("Folder %s has no status from Syncthing yet", folder.Label),
+				SubjectID: folder.ID,
+			})
+			continue
+		}
+
+		// A failed watcher leaves the folder relying on slow periodic rescans.
+		if folder.WatcherEnabled && folder.WatcherError != nil && !strings.EqualFold(folder.State, "paused") {
+			alerts = append(alerts, Alert{
+				Severity:  "warn",
+				Code:      "WATCHER_FAILED",
+				Message:   fmt.Sprintf("Folder %s file watcher failed: %s", folder.Label, *folder.WatcherError),
+				SubjectID: folder.ID,
+			})
+		}
+
+		// Files Syncthing should skip, or keep, depend on patterns it could
+		// not read, so "why isn't this syncing" has no visible answer.
+		if folder.IgnoreError != nil && !strings.EqualFold(folder.State, "paused") {
+			alerts = append(alerts, Alert{
+				Severity:  "warn",
+				Code:      "IGNORE_PARSE_ERROR",
+				Message:   fmt.Sprintf("Folder %s ignore patterns failed to parse: %s", folder.Label, *folder.IgnoreError),
+				SubjectID: folder.ID,
+			})
+		}
+
+		// Local changes only mean something for receive-only folders, where
+		// they diverge from the cluster and may be reverted.
+		if folder.Type == "receiveonly" && folder.LocalChangesItems > 0 {
+			alerts = append(alerts, Alert{
+				Severity:  "warn",
+				Code:      "FOLDER_LOCAL_CHANGES",
+				Message:   fmt.Sprintf("Receive-only folder %s has %d locally changed items (%s)", folder.Label, folder.LocalChangesItems, humanize.Bytes(int64(folder.LocalChangesBytes))),
+				SubjectID: folder.ID,
+			})
+		}
+
 		if folder.NeedItems > 0 || folder.NeedBytes > 0 {
 			alerts = append(alerts, Alert{
 				Severity:  "warn",
@@ -44,3 +154,115 @@ func DeriveAlerts(remotes []RemoteDeviceStatus, folders []FolderStatus) []Alert
 
 	return alerts
 }
+
+// ConnectivityAlerts raises CONNECTIVITY_DEGRADED when fewer than half of the
+// recent peak of connected remotes are connected now. A peak of one is left
+// to REMOTE_DISCONNECTED.
+func ConnectivityAlerts(connected, peak int, deviceID string) []Alert {
+	if peak < 2 || connected*2 >= peak {
+		return nil
+	}
+	return []Alert{{
+		Severity:  "info",
+		Code:      "CONNECTIVITY_DEGRADED",
+		Message:   fmt.Sprintf("Only %d of the %d recently connected remote devices are connected", connected, peak),
+		SubjectID: deviceID,
+	}}
+}
+
+// NotSharedAlerts raises FOLDER_NOT_SHARED for each non-paused folder shared
+// with no remote device, whose data then exists on this device only.
+func NotSharedAlerts(folders []FolderStatus) []Alert {
+	alerts := make([]Alert, 0)
+	for _, folder := range folders {
+		if folder.SharedWith > 0 || strings.EqualFold(folder.State, "paused") {
+			continue
+		}
+		alerts = append(alerts, Alert{
+			Severity:  "info",
+			Code:      "FOLDER_NOT_SHARED",
+			Message:   fmt.Sprintf("Folder %s is not shared with any remote device", folder.Label),
+			SubjectID: folder.ID,
+		})
+	}
+	return alerts
+}
+
+// OverlapAlerts raises FOLDER_PATHS_OVERLAP for each pair of folders where one
+// path is the other or lies inside it, a setup Syncthing only warns about at
+// startup. The alert is about the nested folder and names both.
+func OverlapAlerts(folders []FolderStatus) []Alert {
+	alerts := make([]Alert, 0)
+	for i, outer := range folders {
+		outerPath := normalizeFolderPath(outer.Path)
+		if outerPath == "" {
+			continue
+		}
+		for j, inner := range folders {
+			innerPath := normalizeFolderPath(inner.Path)
+			if i == j || innerPath == "" {
+				continue
+			}
+			// Identical paths are reported once, for the later folder.
+			if innerPath == outerPath && j < i {
+				continue
+			}
+			if innerPath != outerPath && !strings.HasPrefix(innerPath, strings.TrimSuffix(outerPath, "/")+"/") {
+				continue
+			}
+			alerts = append(alerts, Alert{
+				Severity:  "warn",
+				Code:      "FOLDER_PATHS_OVERLAP",
+				Message:   fmt.Sprintf("Folder %s (%s) overlaps folder %s (%s)", inner.Label, inner.Path, outer.Label, outer.Path),
+				SubjectID: inner.ID,
+			})
+		}
+	}
+	return alerts
+}
+
+// normalizeFolderPath cleans a folder path for comparison, treating
+// backslashes as separators and dropping any trailing slash.
+func normalizeFolderPath(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ""
+	}
+	return path.Clean(strings.ReplaceAll(p, `\`, "/"))
+}
+
+// DiskSpaceThresholds configures LOW_DISK_SPACE alerts. A zero value disables
+// the corresponding check.
+type DiskSpaceThresholds struct {
+	MinFreeBytes int64
+	MinFreePct   float64
+}
+
+// DiskSpaceAlerts flags folders whose reported free disk space is below either
+// threshold. Folders without disk information are skipped.
+func DiskSpaceAlerts(folders []FolderStatus, thresholds DiskSpaceThresholds) []Alert {
+	alerts := make([]Alert, 0)
+	for _, folder := range folders {
+		if folder.DiskFreeBytes == nil {
+			continue
+		}
+
+		lowBytes := thresholds.MinFreeBytes > 0 && int64(*folder.DiskFreeBytes) < thresholds.MinFreeBytes
+		lowPct := thresholds.MinFreePct > 0 && folder.DiskFreePct != nil && *folder.DiskFreePct < thresholds.MinFreePct
+		if !lowBytes && !lowPct {
+			continue
+		}
+
+		message := fmt.Sprintf("Folder %s is low on disk space (%d bytes free)", folder.Label, *folder.DiskFreeBytes)
+		if folder.DiskFreePct != nil {
+			message = fmt.Sprintf("Folder %s is low on disk space (%.1f%% free)", folder.Label, *folder.DiskFreePct)
+		}
+		alerts = append(alerts, Alert{
+			Severity:  "warn",
+			Code:      "LOW_DISK_SPACE",
+			Message:   message,
+			SubjectID: folder.ID,
+		})
+	}
+	return alerts
+}
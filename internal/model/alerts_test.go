@@ -0,0 +1,98 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTruncateAlertsKeepsMostSevere(t *testing.T) {
+	var alerts []Alert
+	for i := range 40 {
+		alerts = append(alerts, Alert{Severity: "info", Code: "FOLDER_PENDING", SubjectID: fmt.Sprintf("info-%d", i)})
+	}
+	for i := range 5 {
+		alerts = append(alerts, Alert{Severity: "critical", Code: "FOLDER_ERROR", SubjectID: fmt.Sprintf("critical-%d", i)})
+	}
+	alerts = append(alerts, Alert{Severity: "warn", Code: "FOLDER_OUT_OF_SYNC", SubjectID: "warn-0"})
+
+	got := TruncateAlerts(alerts, 8)
+	if len(got) != 9 {
+		t.Fatalf("expected 8 alerts plus a summary, got %d", len(got))
+	}
+	for i := range 5 {
+		if got[i].Severity != "critical" || got[i].SubjectID != fmt.Sprintf("critical-%d", i) {
+			t.Fatalf("expected critical alerts first in their original order, got %+v", got[:5])
+		}
+	}
+	if got[5].Severity != "warn" || got[6].Severity != "info" {
+		t.Fatalf("expected warn before info, got %+v", got[5:8])
+	}
+	summary := got[8]
+	if summary.Code != "ALERTS_TRUNCATED" || summary.Severity != "info" || summary.Message != "38 more alerts hidden" {
+		t.Fatalf("unexpected truncation summary: %+v", summary)
+	}
+	if alerts[0].SubjectID != "info-0" {
+		t.Fatalf("expected the input slice to be left unchanged")
+	}
+}
+
+func TestTruncateAlertsUnderLimit(t *testing.T) {
+	alerts := []Alert{{Severity: "info", Code: "A"}, {Severity: "critical", Code: "B"}}
+	for _, limit := range []int{0, 2, 5} {
+		got := TruncateAlerts(alerts, limit)
+		if len(got) != 2 || got[0].Code != "A" {
+			t.Fatalf("limit %d: expected alerts unchanged, got %+v", limit, got)
+		}
+	}
+}
+
+func TestSeverityLevel(t *testing.T) {
+	cases := map[string]int{
+		"critical": SeverityLevelCritical,
+		"WARN":     SeverityLevelWarn,
+		"info":     SeverityLevelInfo,
+		"":         SeverityLevelUnknown,
+		"fatal":    SeverityLevelUnknown,
+	}
+	for severity, want := range cases {
+		if got := SeverityLevel(severity); got != want {
+			t.Fatalf("SeverityLevel(%q) = %d, want %d", severity, got, want)
+		}
+	}
+	if SeverityLevelUnknown >= SeverityLevelInfo {
+		t.Fatalf("expected unknown severities to rank below info")
+	}
+}
+
+func TestOverlapAlerts(t *testing.T) {
+	cases := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{name: "disjoint", paths: []string{"/sync/media", "/sync/media-old", "/sync/docs"}},
+		{name: "nested", paths: []string{"/sync/media", "/sync/media/movies", "/sync/docs"}, want: []string{"f1"}},
+		{name: "trailing slash", paths: []string{"/sync/media/", "/sync/./media/movies/"}, want: []string{"f1"}},
+		{name: "same path", paths: []string{"/sync/media", "/sync/media/"}, want: []string{"f1"}},
+		{name: "windows", paths: []string{`C:\Sync`, `C:\Sync\Photos`}, want: []string{"f1"}},
+		{name: "root", paths: []string{"/", "/sync"}, want: []string{"f1"}},
+		{name: "empty path", paths: []string{"", "/sync"}},
+	}
+	for _, tc := range cases {
+		folders := make([]FolderStatus, 0, len(tc.paths))
+		for i, p := range tc.paths {
+			folders = append(folders, FolderStatus{ID: fmt.Sprintf("f%d", i), Label: fmt.Sprintf("F%d", i), Path: p})
+		}
+
+		var got []string
+		for _, alert := range OverlapAlerts(folders) {
+			if alert.Code != "FOLDER_PATHS_OVERLAP" || alert.Severity != "warn" {
+				t.Fatalf("%s: unexpected alert %+v", tc.name, alert)
+			}
+			got = append(got, alert.SubjectID)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Fatalf("%s: expected overlap alerts for %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// ByteCount is a size in bytes. It encodes as a JSON number, or as a JSON
+// string of digits once SetByteCountsAsStrings(true) has been called.
+type ByteCount int64
+
+var byteCountsAsStrings atomic.Bool
+
+// SetByteCountsAsStrings switches how every ByteCount encodes, so JavaScript
+// clients can keep values above 2^53 exact.
+func SetByteCountsAsStrings(on bool) {
+	byteCountsAsStrings.Store(on)
+}
+
+func (b ByteCount) MarshalJSON() ([]byte, error) {
+	if byteCountsAsStrings.Load() {
+		return strconv.AppendQuote(nil, strconv.FormatInt(int64(b), 10)), nil
+	}
+	return strconv.AppendInt(nil, int64(b), 10), nil
+}
+
+// UnmarshalJSON accepts both encodings.
+func (b *ByteCount) UnmarshalJSON(data []byte) error {
+	if len(data) > 1 && data[0] == '"' {
+		data = data[1 : len(data)-1]
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	*b = ByteCount(n)
+	return nil
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestByteCountEncodingModes(t *testing.T) {
+	value := struct {
+		NeedBytes ByteCount `json:"need_bytes"`
+	}{NeedBytes: 1<<53 + 1}
+
+	plain, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetByteCountsAsStrings(true)
+	t.Cleanup(func() { SetByteCountsAsStrings(false) })
+	quoted, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(plain); got != `{"need_bytes":9007199254740993}` {
+		t.Fatalf("expected a JSON number by default, got %s", got)
+	}
+	if got := string(quoted); got != `{"need_bytes":"9007199254740993"}` {
+		t.Fatalf("expected the exact digits as a string, got %s", got)
+	}
+	for _, body := range [][]byte{plain, quoted} {
+		var decoded struct {
+			NeedBytes ByteCount `json:"need_bytes"`
+		}
+		if err := json.Unmarshal(body, &decoded); err != nil || decoded.NeedBytes != value.NeedBytes {
+			t.Fatalf("expected %s to decode back to %d, got %d (%v)", body, value.NeedBytes, decoded.NeedBytes, err)
+		}
+	}
+}
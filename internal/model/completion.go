@@ -0,0 +1,23 @@
+package model
+
+import "strings"
+
+// OverallCompletion averages the completion of non-paused folders weighted by
+// their global size, so a large folder far behind outweighs many small ones
+// in sync. Folders without a completion are skipped; nil means no folder
+// contributed.
+func OverallCompletion(folders []FolderStatus) *float64 {
+	var weighted, totalBytes float64
+	for _, folder := range folders {
+		if folder.CompletionPct == nil || strings.EqualFold(folder.State, "paused") || folder.GlobalBytes <= 0 {
+			continue
+		}
+		weighted += *folder.CompletionPct * float64(folder.GlobalBytes)
+		totalBytes += float64(folder.GlobalBytes)
+	}
+	if totalBytes == 0 {
+		return nil
+	}
+	pct := weighted / totalBytes
+	return &pct
+}
@@ -0,0 +1,29 @@
+package model
+
+import "testing"
+
+func TestOverallCompletionIsWeightedByGlobalBytes(t *testing.T) {
+	pct := func(v float64) *float64 { return &v }
+	folders := []FolderStatus{
+		{ID: "large", State: "syncing", GlobalBytes: 1000, CompletionPct: pct(50)},
+		{ID: "tiny", State: "idle", GlobalBytes: 10, CompletionPct: pct(100)},
+		{ID: "paused", State: "paused", GlobalBytes: 100000, CompletionPct: pct(0)},
+		{ID: "unknown", State: "idle", GlobalBytes: 100000},
+	}
+
+	got := OverallCompletion(folders)
+	if got == nil {
+		t.Fatalf("expected an overall completion")
+	}
+	want := (50*1000 + 100*10) / 1010.0
+	if *got != want {
+		t.Fatalf("expected %f, got %f", want, *got)
+	}
+	if *got > 51 {
+		t.Fatalf("expected the large folder to dominate, got %f", *got)
+	}
+
+	if OverallCompletion(folders[2:]) != nil {
+		t.Fatalf("expected nil when only paused or unknown folders remain")
+	}
+}
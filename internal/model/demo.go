@@ -0,0 +1,12 @@
+package model
+
+// Demo scenarios accepted by the demo collector.
+const (
+	DemoScenarioMixed    = "mixed"
+	DemoScenarioHealthy  = "healthy"
+	DemoScenarioDegraded = "degraded"
+	DemoScenarioEmpty    = "empty"
+)
+
+// DemoScenarios lists the valid demo scenarios, default first.
+var DemoScenarios = []string{DemoScenarioMixed, DemoScenarioHealthy, DemoScenarioDegraded, DemoScenarioEmpty}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+)
+
+// MeaningfulEquals reports whether s and other show the same dashboard state
+// for notification purposes: source and health status, folder states,
+// completion and need, alerts, and remote connectivity. Timestamps, ages,
+// byte rates and display strings are ignored, as is the order of folders,
+// remotes and alerts.
+func (s *DashboardSnapshot) MeaningfulEquals(other *DashboardSnapshot) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	if s.SourceOnline != other.SourceOnline || s.Stale != other.Stale || s.Health != other.Health {
+		return false
+	}
+	if !equalFloatPtr(s.Device.OverallCompletionPct, other.Device.OverallCompletionPct) {
+		return false
+	}
+	return sameKeys(s.Folders, other.Folders, folderKey) &&
+		sameKeys(s.Remotes, other.Remotes, remoteKey) &&
+		sameKeys(s.Alerts, other.Alerts, func(alert Alert) Alert { return alert })
+}
+
+// MeaningfulID returns a short hash of the content MeaningfulEquals compares,
+// so two snapshots share an ID exactly when they are meaningfully equal.
+func (s *DashboardSnapshot) MeaningfulID() string {
+	overall := "none"
+	if s.Device.OverallCompletionPct != nil {
+		overall = fmt.Sprint(*s.Device.OverallCompletionPct)
+	}
+	lines := []string{fmt.Sprintf("snapshot %t %t %q %s", s.SourceOnline, s.Stale, s.Health, overall)}
+	lines = append(lines, sortedKeys(s.Folders, folderKey)...)
+	lines = append(lines, sortedKeys(s.Remotes, remoteKey)...)
+	lines = append(lines, sortedKeys(s.Alerts, func(alert Alert) Alert { return alert })...)
+
+	sum := sha256.New()
+	for _, line := range lines {
+		fmt.Fprintln(sum, line)
+	}
+	return hex.EncodeToString(sum.Sum(nil))[:snapshotIDLength]
+}
+
+// snapshotIDLength is how many hex digits of the hash MeaningfulID keeps.
+const snapshotIDLength = 12
+
+// IsSnapshotID reports whether id has the shape MeaningfulID produces.
+func IsSnapshotID(id string) bool {
+	if len(id) != snapshotIDLength {
+		return false
+	}
+	for _, c := range id {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedKeys renders each item's key, sorted so the order of items does not
+// matter. The key type prefixes each line to keep the groups apart.
+func sortedKeys[T any, K comparable](items []T, key func(T) K) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, fmt.Sprintf("%#v", key(item)))
+	}
+	slices.Sort(out)
+	return out
+}
+
+type meaningfulFolder struct {
+	id            string
+	state         string
+	completionPct float64
+	hasCompletion bool
+	needItems     int64
+	needBytes     int64
+	watcherError  string
+	ignoreError   string
+}
+
+func folderKey(folder FolderStatus) meaningfulFolder {
+	key := meaningfulFolder{
+		id:        folder.ID,
+		state:     folder.State,
+		needItems: folder.NeedItems,
+		needBytes: int64(folder.NeedBytes),
+	}
+	if folder.CompletionPct != nil {
+		key.completionPct = *folder.CompletionPct
+		key.hasCompletion = true
+	}
+	if folder.WatcherError != nil {
+		key.watcherError = *folder.WatcherError
+	}
+	if folder.IgnoreError != nil {
+		key.ignoreError = *folder.IgnoreError
+	}
+	return key
+}
+
+type meaningfulRemote struct {
+	id        string
+	connected bool
+}
+
+func remoteKey(remote RemoteDeviceStatus) meaningfulRemote {
+	return meaningfulRemote{id: remote.ID, connected: remote.Connected}
+}
+
+// sameKeys reports whether a and b map to the same keys, counting duplicates,
+// in any order.
+func sameKeys[T any, K comparable](a, b []T, key func(T) K) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[K]int, len(a))
+	for _, item := range a {
+		counts[key(item)]++
+	}
+	for _, item := range b {
+		k := key(item)
+		if counts[k] == 0 {
+			return false
+		}
+		counts[k]--
+	}
+	return true
+}
+
+func equalFloatPtr(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
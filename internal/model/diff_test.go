@@ -0,0 +1,103 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func diffSnapshot() DashboardSnapshot {
+	completion := 100.0
+	snapshot := DashboardSnapshot{
+		GeneratedAt:  time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		SourceOnline: true,
+		Device:       DeviceStatus{DownloadBPS: 1200, UploadBPS: 300, OverallCompletionPct: &completion},
+		Folders: []FolderStatus{
+			{ID: "photos", State: "idle", CompletionPct: &completion},
+			{ID: "docs", State: "idle", CompletionPct: &completion},
+		},
+		Remotes: []RemoteDeviceStatus{{ID: "laptop", Connected: true}},
+	}
+	snapshot.UpdateHealth()
+	return snapshot
+}
+
+func TestMeaningfulEqualsIgnoresRatesAndTimestamps(t *testing.T) {
+	before := diffSnapshot()
+	after := diffSnapshot()
+	after.GeneratedAt = after.GeneratedAt.Add(10 * time.Second)
+	after.CollectDurationMS = 42
+	after.Device.DownloadBPS = 98000
+	after.Device.UploadBPS = 0
+	after.Device.DownloadDisplay = "95.7 KiB/s"
+	after.Folders[0], after.Folders[1] = after.Folders[1], after.Folders[0]
+
+	if !before.MeaningfulEquals(&after) {
+		t.Fatalf("expected only rate and timestamp changes to be insignificant")
+	}
+}
+
+func TestMeaningfulEqualsDetectsFolderError(t *testing.T) {
+	before := diffSnapshot()
+	after := diffSnapshot()
+	after.Folders[1].State = "error"
+	after.Alerts = []Alert{{Severity: "critical", Code: "FOLDER_ERROR", SubjectID: "docs"}}
+	after.UpdateHealth()
+
+	if before.MeaningfulEquals(&after) {
+		t.Fatalf("expected folder error to be significant")
+	}
+}
+
+func TestMeaningfulEqualsDetectsRemoteDisconnect(t *testing.T) {
+	before := diffSnapshot()
+	after := diffSnapshot()
+	after.Remotes[0].Connected = false
+
+	if before.MeaningfulEquals(&after) {
+		t.Fatalf("expected remote disconnect to be significant")
+	}
+}
+
+func TestMeaningfulIDFollowsMeaningfulChanges(t *testing.T) {
+	before := diffSnapshot()
+	id := before.MeaningfulID()
+	if len(id) != 12 {
+		t.Fatalf("expected a 12-character ID, got %q", id)
+	}
+
+	rates := diffSnapshot()
+	rates.GeneratedAt = rates.GeneratedAt.Add(10 * time.Second)
+	rates.Device.DownloadBPS = 98000
+	rates.Folders[0], rates.Folders[1] = rates.Folders[1], rates.Folders[0]
+	if got := rates.MeaningfulID(); got != id {
+		t.Fatalf("expected rate and order changes to keep ID %q, got %q", id, got)
+	}
+
+	folderError := diffSnapshot()
+	folderError.Folders[1].State = "error"
+	disconnected := diffSnapshot()
+	disconnected.Remotes[0].Connected = false
+	alerted := diffSnapshot()
+	alerted.Alerts = []Alert{{Severity: "info", Code: "FOLDER_PENDING", SubjectID: "docs"}}
+
+	seen := map[string]bool{id: true}
+	for name, snapshot := range map[string]DashboardSnapshot{"folder error": folderError, "disconnect": disconnected, "alert": alerted} {
+		got := snapshot.MeaningfulID()
+		if seen[got] {
+			t.Fatalf("%s: expected a new ID, got %q again", name, got)
+		}
+		seen[got] = true
+	}
+}
+
+func TestIsSnapshotIDMatchesMeaningfulID(t *testing.T) {
+	snapshot := DashboardSnapshot{SourceOnline: true}
+	if id := snapshot.MeaningfulID(); !IsSnapshotID(id) {
+		t.Fatalf("expected %q to be a snapshot ID", id)
+	}
+	for _, id := range []string{"", "2026-02-06T10:00:00Z", "ABCDEF012345", "abcdef01234", "abcdef0123456"} {
+		if IsSnapshotID(id) {
+			t.Fatalf("expected %q not to be a snapshot ID", id)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"syncthing-dashboard/internal/humanize"
+)
+
+// PopulateDisplay fills the human-readable *Display fields from the raw byte
+// counts and rates, so every consumer formats them the same way.
+func (s *DashboardSnapshot) PopulateDisplay() {
+	s.Device.DownloadDisplay = humanize.BytesPerSecond(s.Device.DownloadBPS)
+	s.Device.UploadDisplay = humanize.BytesPerSecond(s.Device.UploadBPS)
+	s.Device.LocalBytesTotalDisplay = humanize.Bytes(int64(s.Device.LocalBytesTotal))
+
+	for i := range s.Folders {
+		folder := &s.Folders[i]
+		folder.GlobalBytesDisplay = humanize.Bytes(int64(folder.GlobalBytes))
+		folder.LocalBytesDisplay = humanize.Bytes(int64(folder.LocalBytes))
+		folder.NeedBytesDisplay = humanize.Bytes(int64(folder.NeedBytes))
+	}
+}
+
+// PopulateAges fills the *SecondsAgo fields relative to now, so clients show
+// the same relative times whatever their own clock says.
+func (s *DashboardSnapshot) PopulateAges(now time.Time) {
+	for i := range s.Remotes {
+		s.Remotes[i].LastSeenSecondsAgo = secondsAgo(s.Remotes[i].LastSeenAt, now)
+	}
+	for i := range s.Folders {
+		s.Folders[i].LastScanSecondsAgo = secondsAgo(s.Folders[i].LastScanAt, now)
+	}
+}
+
+// secondsAgo returns whole seconds from t to now, never negative, or nil
+// when t is unknown.
+func secondsAgo(t *time.Time, now time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	seconds := max(0, int64(now.Sub(*t)/time.Second))
+	return &seconds
+}
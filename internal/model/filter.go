@@ -0,0 +1,30 @@
+package model
+
+import "path"
+
+// Filter selects items by ID using glob patterns as understood by path.Match.
+// An empty Include admits everything; Exclude always takes precedence.
+type Filter struct {
+	Include []string
+	Exclude []string
+}
+
+// Allows reports whether any of the given keys passes the filter. Exclude
+// is checked against every key before Include is considered.
+func (f Filter) Allows(keys ...string) bool {
+	if matchesAny(f.Exclude, keys) {
+		return false
+	}
+	return len(f.Include) == 0 || matchesAny(f.Include, keys)
+}
+
+func matchesAny(patterns, keys []string) bool {
+	for _, pattern := range patterns {
+		for _, key := range keys {
+			if matched, _ := path.Match(pattern, key); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package model
+
+import "testing"
+
+func TestFilterAllows(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		id     string
+		want   bool
+	}{
+		{"empty filter admits all", Filter{}, "photos", true},
+		{"exact include", Filter{Include: []string{"photos"}}, "photos", true},
+		{"not included", Filter{Include: []string{"photos"}}, "docs", false},
+		{"glob include", Filter{Include: []string{"team-*"}}, "team-design", true},
+		{"glob include miss", Filter{Include: []string{"team-*"}}, "personal-design", false},
+		{"character class", Filter{Include: []string{"disk[0-9]"}}, "disk3", true},
+		{"exclude only", Filter{Exclude: []string{"private-*"}}, "private-keys", false},
+		{"exclude only passes others", Filter{Exclude: []string{"private-*"}}, "photos", true},
+		{"exclude wins over include", Filter{Include: []string{"team-*"}, Exclude: []string{"team-secret"}}, "team-secret", false},
+		{"include with exclude passes others", Filter{Include: []string{"team-*"}, Exclude: []string{"team-secret"}}, "team-design", true},
+		{"exclude all", Filter{Exclude: []string{"*"}}, "anything", false},
+	}
+
+	for _, tc := range tests {
+		if got := tc.filter.Allows(tc.id); got != tc.want {
+			t.Errorf("%s: Allows(%q) = %v, want %v", tc.name, tc.id, got, tc.want)
+		}
+	}
+}
+
+func TestFilterAllowsMatchesAnyKey(t *testing.T) {
+	filter := Filter{Include: []string{"Laptop"}, Exclude: []string{"OLDPHONE-*"}}
+	if !filter.Allows("ABCDEFG-1234567", "Laptop") {
+		t.Fatalf("expected include to match the second key")
+	}
+	if filter.Allows("OLDPHONE-1234567", "Laptop") {
+		t.Fatalf("expected exclude on any key to win")
+	}
+}
@@ -0,0 +1,11 @@
+package model
+
+// FolderGroups maps folder IDs to the display group they belong to.
+type FolderGroups map[string]string
+
+// Apply sets each folder's Group from the mapping; unlisted folders get none.
+func (g FolderGroups) Apply(folders []FolderStatus) {
+	for i := range folders {
+		folders[i].Group = g[folders[i].ID]
+	}
+}
@@ -0,0 +1,31 @@
+package model
+
+// Overall snapshot health values.
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+)
+
+// UpdateHealth fills in each alert's SeverityLevel and sets Health from the
+// source state and alerts: down while the Syncthing API is offline, degraded
+// while any critical or warn alert is active, and ok otherwise. Call it after
+// the alerts are final.
+func (s *DashboardSnapshot) UpdateHealth() {
+	degraded := false
+	for i := range s.Alerts {
+		s.Alerts[i].SeverityLevel = SeverityLevel(s.Alerts[i].Severity)
+		if s.Alerts[i].SeverityLevel >= SeverityLevelWarn {
+			degraded = true
+		}
+	}
+
+	switch {
+	case !s.SourceOnline:
+		s.Health = HealthDown
+	case degraded:
+		s.Health = HealthDegraded
+	default:
+		s.Health = HealthOK
+	}
+}
@@ -0,0 +1,42 @@
+package model
+
+import "testing"
+
+func TestUpdateHealth(t *testing.T) {
+	cases := []struct {
+		name   string
+		online bool
+		alerts []Alert
+		want   string
+	}{
+		{"online without alerts", true, nil, HealthOK},
+		{"online with info alert", true, []Alert{{Severity: "info", Code: "CONFIG_EMPTY"}}, HealthOK},
+		{"online with warn alert", true, []Alert{{Severity: "warn", Code: "FOLDER_OUT_OF_SYNC"}}, HealthDegraded},
+		{"online with critical alert", true, []Alert{{Severity: "info"}, {Severity: "critical", Code: "FOLDER_ERROR"}}, HealthDegraded},
+		{"offline without alerts", false, nil, HealthDown},
+		{"offline with critical alert", false, []Alert{{Severity: "critical", Code: "SOURCE_UNREACHABLE"}}, HealthDown},
+	}
+
+	for _, tc := range cases {
+		snapshot := DashboardSnapshot{SourceOnline: tc.online, Alerts: tc.alerts}
+		snapshot.UpdateHealth()
+		if snapshot.Health != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.want, snapshot.Health)
+		}
+	}
+}
+
+func TestUpdateHealthFillsSeverityLevels(t *testing.T) {
+	snapshot := DashboardSnapshot{
+		SourceOnline: true,
+		Alerts:       []Alert{{Severity: "critical"}, {Severity: "info"}, {Severity: "bogus"}},
+	}
+	snapshot.UpdateHealth()
+
+	want := []int{SeverityLevelCritical, SeverityLevelInfo, SeverityLevelUnknown}
+	for i, alert := range snapshot.Alerts {
+		if alert.SeverityLevel != want[i] {
+			t.Fatalf("alert %d: expected level %d, got %d", i, want[i], alert.SeverityLevel)
+		}
+	}
+}
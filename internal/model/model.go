@@ -4,59 +4,138 @@ import "time"
 
 // DashboardSnapshot is the API payload returned to dashboard clients.
 type DashboardSnapshot struct {
-	GeneratedAt  time.Time            `json:"generated_at"`
-	SourceOnline bool                 `json:"source_online"`
-	SourceError  *string              `json:"source_error"`
-	Device       DeviceStatus         `json:"device"`
-	Folders      []FolderStatus       `json:"folders"`
-	Remotes      []RemoteDeviceStatus `json:"remotes"`
-	Alerts       []Alert              `json:"alerts"`
-	Stale        bool                 `json:"stale"`
+	GeneratedAt time.Time `json:"generated_at"`
+	// SnapshotID changes exactly when the snapshot changes meaningfully; see
+	// MeaningfulID.
+	SnapshotID   string `json:"snapshot_id"`
+	SourceOnline bool   `json:"source_online"`
+	// Demo is set on snapshots of synthetic demonstration data.
+	Demo              bool                 `json:"demo"`
+	SourceError       *string              `json:"source_error"`
+	Device            DeviceStatus         `json:"device"`
+	Folders           []FolderStatus       `json:"folders"`
+	Remotes           []RemoteDeviceStatus `json:"remotes"`
+	Alerts            []Alert              `json:"alerts"`
+	Stale             bool                 `json:"stale"`
+	Health            string               `json:"health"`
+	CollectDurationMS int64                `json:"collect_duration_ms"`
 }
 
 type DeviceStatus struct {
-	Name            string  `json:"name"`
-	ID              string  `json:"id"`
-	Version         string  `json:"version"`
-	UptimeS         int64   `json:"uptime_s"`
-	DownloadBPS     float64 `json:"download_bps"`
-	UploadBPS       float64 `json:"upload_bps"`
-	LocalFilesTotal int64   `json:"local_files_total"`
-	LocalDirsTotal  int64   `json:"local_dirs_total"`
-	LocalBytesTotal int64   `json:"local_bytes_total"`
-	ListenersOK     int     `json:"listeners_ok"`
-	ListenersTotal  int     `json:"listeners_total"`
-	DiscoveryOK     int     `json:"discovery_ok"`
-	DiscoveryTotal  int     `json:"discovery_total"`
+	Name            string    `json:"name"`
+	ID              string    `json:"id"`
+	Version         string    `json:"version"`
+	UptimeS         int64     `json:"uptime_s"`
+	DownloadBPS     float64   `json:"download_bps"`
+	UploadBPS       float64   `json:"upload_bps"`
+	LocalFilesTotal int64     `json:"local_files_total"`
+	LocalDirsTotal  int64     `json:"local_dirs_total"`
+	LocalBytesTotal ByteCount `json:"local_bytes_total"`
+	ListenersOK     int       `json:"listeners_ok"`
+	ListenersTotal  int       `json:"listeners_total"`
+	DiscoveryOK     int       `json:"discovery_ok"`
+	DiscoveryTotal  int       `json:"discovery_total"`
+	// DiscoveryDetails lists each discovery method by name, sorted.
+	DiscoveryDetails []DiscoveryMethodStatus `json:"discovery_details"`
+	// RemotesConnectedPeak is the most remotes connected at once within the
+	// connectivity window, including now.
+	RemotesConnectedPeak int `json:"remotes_connected_peak"`
+	// OverallCompletionPct is the size-weighted completion of non-paused folders.
+	OverallCompletionPct *float64 `json:"overall_completion_pct"`
+	// SessionInBytes and SessionOutBytes are the bytes received and sent
+	// since the dashboard started watching, across Syncthing restarts.
+	SessionInBytes  ByteCount `json:"session_in_bytes"`
+	SessionOutBytes ByteCount `json:"session_out_bytes"`
+
+	DownloadDisplay        string `json:"download_display,omitempty"`
+	UploadDisplay          string `json:"upload_display,omitempty"`
+	LocalBytesTotalDisplay string `json:"local_bytes_total_display,omitempty"`
 }
 
 type FolderStatus struct {
-	ID                string     `json:"id"`
-	Label             string     `json:"label"`
-	Path              string     `json:"path"`
-	State             string     `json:"state"`
-	GlobalFiles       int64      `json:"global_files"`
-	LocalFiles        int64      `json:"local_files"`
-	GlobalBytes       int64      `json:"global_bytes"`
-	LocalBytes        int64      `json:"local_bytes"`
-	NeedItems         int64      `json:"need_items"`
-	NeedBytes         int64      `json:"need_bytes"`
-	LocalChangesItems int64      `json:"local_changes_items"`
-	CompletionPct     *float64   `json:"completion_pct"`
-	LastScanAt        *time.Time `json:"last_scan_at"`
+	ID                string    `json:"id"`
+	Label             string    `json:"label"`
+	Path              string    `json:"path"`
+	Type              string    `json:"type"`
+	Group             string    `json:"group"`
+	State             string    `json:"state"`
+	GlobalFiles       int64     `json:"global_files"`
+	LocalFiles        int64     `json:"local_files"`
+	GlobalBytes       ByteCount `json:"global_bytes"`
+	LocalBytes        ByteCount `json:"local_bytes"`
+	NeedItems         int64     `json:"need_items"`
+	NeedBytes         ByteCount `json:"need_bytes"`
+	NeedFiles         int64     `json:"need_files"`
+	NeedDirectories   int64     `json:"need_directories"`
+	NeedSymlinks      int64     `json:"need_symlinks"`
+	NeedDeletes       int64     `json:"need_deletes"`
+	LocalChangesItems int64     `json:"local_changes_items"`
+	LocalChangesBytes ByteCount `json:"local_changes_bytes"`
+	CompletionPct     *float64  `json:"completion_pct"`
+	// ScanProgressPct is how far the current scan is, set only while State is
+	// "scanning" and progress has been reported.
+	ScanProgressPct    *float64   `json:"scan_progress_pct"`
+	LastScanAt         *time.Time `json:"last_scan_at"`
+	LastScanSecondsAgo *int64     `json:"last_scan_seconds_ago"`
+	LastSyncedFile     *string    `json:"last_synced_file"`
+	LastSyncedAt       *time.Time `json:"last_synced_at"`
+	DiskFreeBytes      *ByteCount `json:"disk_free_bytes"`
+	DiskFreePct        *float64   `json:"disk_free_pct"`
+	WatcherEnabled     bool       `json:"watcher_enabled"`
+	WatcherError       *string    `json:"watcher_error"`
+	// SharedWith counts the remote devices the folder is shared with.
+	SharedWith int `json:"shared_with"`
+	// Contributors is filled only when contributor collection is enabled.
+	Contributors []FolderContributor `json:"contributors,omitempty"`
+	// IgnorePatterns and IgnoreError are filled only when ignore collection
+	// is enabled. IgnoreError is why the ignore patterns failed to parse.
+	IgnorePatterns int     `json:"ignore_patterns"`
+	IgnoreError    *string `json:"ignore_error"`
+
+	GlobalBytesDisplay string `json:"global_bytes_display,omitempty"`
+	LocalBytesDisplay  string `json:"local_bytes_display,omitempty"`
+	NeedBytesDisplay   string `json:"need_bytes_display,omitempty"`
+}
+
+// DiscoveryMethodStatus is one discovery method's state, e.g. global
+// discovery failing to announce.
+type DiscoveryMethodStatus struct {
+	Name  string  `json:"name"`
+	OK    bool    `json:"ok"`
+	Error *string `json:"error"`
+}
+
+// FolderContributor is one remote device's progress on a shared folder.
+type FolderContributor struct {
+	DeviceID      string    `json:"device_id"`
+	DeviceName    string    `json:"device_name"`
+	CompletionPct float64   `json:"completion_pct"`
+	NeedBytes     ByteCount `json:"need_bytes"`
 }
 
 type RemoteDeviceStatus struct {
-	ID         string     `json:"id"`
-	Name       string     `json:"name"`
-	Connected  bool       `json:"connected"`
-	Address    string     `json:"address"`
-	LastSeenAt *time.Time `json:"last_seen_at"`
+	ID                 string     `json:"id"`
+	Name               string     `json:"name"`
+	Connected          bool       `json:"connected"`
+	Address            string     `json:"address"`
+	ConnScheme         string     `json:"conn_scheme"`
+	ConnHost           string     `json:"conn_host"`
+	ConnPort           string     `json:"conn_port"`
+	LastSeenAt         *time.Time `json:"last_seen_at"`
+	LastSeenSecondsAgo *int64     `json:"last_seen_seconds_ago"`
+	LastError          *string    `json:"last_error"`
+	CompletionPct      *float64   `json:"completion_pct"`
+	// DownloadBPS and UploadBPS are the current transfer rates with this
+	// device, derived from its byte totals between polls.
+	DownloadBPS float64 `json:"download_bps"`
+	UploadBPS   float64 `json:"upload_bps"`
 }
 
 type Alert struct {
-	Severity  string `json:"severity"`
-	Code      string `json:"code"`
-	Message   string `json:"message"`
-	SubjectID string `json:"subject_id"`
+	Severity string `json:"severity"`
+	// SeverityLevel is Severity as a number for sorting; see SeverityLevel.
+	SeverityLevel int    `json:"severity_level"`
+	Code          string `json:"code"`
+	Message       string `json:"message"`
+	SubjectID     string `json:"subject_id"`
 }
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Semver is a parsed vX.Y.Z version.
+type Semver struct {
+	Major, Minor, Patch int
+}
+
+// ParseSemver parses versions such as "v2.0.12", "2.0" or "v1.27.3-rc.1+build".
+// Pre-release and build suffixes, and anything after the first space (as in
+// "v2.0.12 linux amd64"), are ignored. A missing patch or minor is zero.
+func ParseSemver(raw string) (Semver, bool) {
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return Semver{}, false
+	}
+	core := strings.TrimPrefix(strings.TrimPrefix(fields[0], "v"), "V")
+	if idx := strings.IndexAny(core, "-+"); idx >= 0 {
+		core = core[:idx]
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return Semver{}, false
+	}
+	var nums [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Semver{}, false
+		}
+		nums[i] = n
+	}
+	return Semver{Major: nums[0], Minor: nums[1], Patch: nums[2]}, true
+}
+
+// Less reports whether v is older than other.
+func (v Semver) Less(other Semver) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+func (v Semver) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// VersionAlerts raises VERSION_OUTDATED when running is older than minimum.
+// It returns nothing when minimum is empty or either version cannot be parsed.
+func VersionAlerts(running, minimum, subjectID string) []Alert {
+	if strings.TrimSpace(minimum) == "" {
+		return nil
+	}
+	minVersion, ok := ParseSemver(minimum)
+	if !ok {
+		return nil
+	}
+	current, ok := ParseSemver(running)
+	if !ok || !current.Less(minVersion) {
+		return nil
+	}
+	return []Alert{{
+		Severity:  "info",
+		Code:      "VERSION_OUTDATED",
+		Message:   fmt.Sprintf("Syncthing %s is older than the minimum supported version %s", current, minVersion),
+		SubjectID: subjectID,
+	}}
+}
@@ -0,0 +1,48 @@
+package model
+
+import "testing"
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Semver
+		ok   bool
+	}{
+		{"v2.0.12", Semver{2, 0, 12}, true},
+		{"v2.0.12 linux amd64", Semver{2, 0, 12}, true},
+		{"1.27.3-rc.1+build.5", Semver{1, 27, 3}, true},
+		{"v2.1", Semver{2, 1, 0}, true},
+		{"", Semver{}, false},
+		{"unknown-dev", Semver{}, false},
+		{"v1.2.3.4", Semver{}, false},
+	}
+
+	for _, tc := range tests {
+		got, ok := ParseSemver(tc.raw)
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("ParseSemver(%q) = %v, %v; want %v, %v", tc.raw, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestVersionAlerts(t *testing.T) {
+	tests := []struct {
+		running string
+		minimum string
+		want    bool
+	}{
+		{"v1.27.3 linux amd64", "v2.0.0", true},
+		{"v2.0.12 linux amd64", "v2.0.12", false},
+		{"v2.1.0 linux amd64", "v2.0.12", false},
+		{"v2.0.11-rc.1", "2.0.12", true},
+		{"v1.0.0", "", false},
+		{"unknown", "v2.0.0", false},
+	}
+
+	for _, tc := range tests {
+		alerts := VersionAlerts(tc.running, tc.minimum, "LOCAL")
+		if got := len(alerts) == 1 && alerts[0].Code == "VERSION_OUTDATED" && alerts[0].Severity == "info"; got != tc.want {
+			t.Errorf("VersionAlerts(%q, %q) = %+v, want outdated=%v", tc.running, tc.minimum, alerts, tc.want)
+		}
+	}
+}
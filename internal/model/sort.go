@@ -0,0 +1,85 @@
+package model
+
+import (
+	"sort"
+	"strings"
+)
+
+// Folder sort modes accepted by SortFolders.
+const (
+	FolderSortLabel      = "label"
+	FolderSortState      = "state"
+	FolderSortCompletion = "completion"
+	FolderSortNeedBytes  = "need_bytes"
+)
+
+// Remote sort modes accepted by SortRemotes.
+const (
+	RemoteSortName       = "name"
+	RemoteSortConnection = "connection"
+)
+
+// FolderSortModes lists the valid folder sort modes, default first.
+var FolderSortModes = []string{FolderSortLabel, FolderSortState, FolderSortCompletion, FolderSortNeedBytes}
+
+// RemoteSortModes lists the valid remote sort modes, default first.
+var RemoteSortModes = []string{RemoteSortName, RemoteSortConnection}
+
+// SortFolders orders folders by label, then stably by mode so folders with
+// equal keys stay in label order. Unknown modes sort by label only.
+func SortFolders(folders []FolderStatus, mode string) {
+	sort.SliceStable(folders, func(i, j int) bool {
+		return folders[i].Label < folders[j].Label
+	})
+
+	switch mode {
+	case FolderSortState:
+		sort.SliceStable(folders, func(i, j int) bool {
+			return folderStateRank(folders[i].State) < folderStateRank(folders[j].State)
+		})
+	case FolderSortCompletion:
+		// Least complete first; folders with unknown completion go last.
+		sort.SliceStable(folders, func(i, j int) bool {
+			a, b := folders[i].CompletionPct, folders[j].CompletionPct
+			if a == nil || b == nil {
+				return a != nil && b == nil
+			}
+			return *a < *b
+		})
+	case FolderSortNeedBytes:
+		sort.SliceStable(folders, func(i, j int) bool {
+			return folders[i].NeedBytes > folders[j].NeedBytes
+		})
+	}
+}
+
+// folderStateRank puts errors first, then active syncing, then pending work,
+// then idle folders, and paused folders last.
+func folderStateRank(state string) int {
+	switch strings.ToLower(state) {
+	case "error":
+		return 0
+	case "syncing", "sync-preparing":
+		return 1
+	case "scanning", "scan-waiting", "sync-waiting", "cleaning", "clean-waiting":
+		return 2
+	case "paused":
+		return 4
+	default:
+		return 3
+	}
+}
+
+// SortRemotes orders remotes by name, then stably by mode. Unknown modes sort
+// by name only.
+func SortRemotes(remotes []RemoteDeviceStatus, mode string) {
+	sort.SliceStable(remotes, func(i, j int) bool {
+		return remotes[i].Name < remotes[j].Name
+	})
+
+	if mode == RemoteSortConnection {
+		sort.SliceStable(remotes, func(i, j int) bool {
+			return !remotes[i].Connected && remotes[j].Connected
+		})
+	}
+}
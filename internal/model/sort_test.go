@@ -0,0 +1,78 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func sortFixtureFolders() []FolderStatus {
+	pct := func(v float64) *float64 { return &v }
+	return []FolderStatus{
+		{Label: "Music", State: "syncing", CompletionPct: pct(64), NeedBytes: 300},
+		{Label: "Backups", State: "paused", CompletionPct: pct(100)},
+		{Label: "Videos", State: "error", CompletionPct: pct(72), NeedBytes: 900},
+		{Label: "Documents", State: "idle", CompletionPct: pct(100)},
+		{Label: "Projects", State: "syncing", CompletionPct: pct(12), NeedBytes: 300},
+		{Label: "Unknown", State: "idle"},
+		{Label: "Downloads", State: "scan-waiting", CompletionPct: pct(100)},
+	}
+}
+
+func folderLabels(folders []FolderStatus) []string {
+	labels := make([]string, 0, len(folders))
+	for _, folder := range folders {
+		labels = append(labels, folder.Label)
+	}
+	return labels
+}
+
+func TestSortFolders(t *testing.T) {
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		{FolderSortLabel, []string{"Backups", "Documents", "Downloads", "Music", "Projects", "Unknown", "Videos"}},
+		{FolderSortState, []string{"Videos", "Music", "Projects", "Downloads", "Documents", "Unknown", "Backups"}},
+		{FolderSortCompletion, []string{"Projects", "Music", "Videos", "Backups", "Documents", "Downloads", "Unknown"}},
+		{FolderSortNeedBytes, []string{"Videos", "Music", "Projects", "Backups", "Documents", "Downloads", "Unknown"}},
+		{"bogus", []string{"Backups", "Documents", "Downloads", "Music", "Projects", "Unknown", "Videos"}},
+	}
+
+	for _, tc := range tests {
+		folders := sortFixtureFolders()
+		SortFolders(folders, tc.mode)
+		if got := folderLabels(folders); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("SortFolders(%q) = %v, want %v", tc.mode, got, tc.want)
+		}
+	}
+}
+
+func TestSortRemotes(t *testing.T) {
+	fixture := func() []RemoteDeviceStatus {
+		return []RemoteDeviceStatus{
+			{Name: "Desk", Connected: true},
+			{Name: "Keyring", Connected: false},
+			{Name: "Attic", Connected: true},
+			{Name: "Backpack", Connected: false},
+		}
+	}
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		{RemoteSortName, []string{"Attic", "Backpack", "Desk", "Keyring"}},
+		{RemoteSortConnection, []string{"Backpack", "Keyring", "Attic", "Desk"}},
+	}
+
+	for _, tc := range tests {
+		remotes := fixture()
+		SortRemotes(remotes, tc.mode)
+		got := make([]string, 0, len(remotes))
+		for _, remote := range remotes {
+			got = append(got, remote.Name)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("SortRemotes(%q) = %v, want %v", tc.mode, got, tc.want)
+		}
+	}
+}
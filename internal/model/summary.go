@@ -0,0 +1,49 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Summary is the top-line view of a snapshot for small status widgets. Its
+// size depends only on the number of distinct folder states, not on how many
+// folders or remotes there are.
+type Summary struct {
+	GeneratedAt      time.Time      `json:"generated_at"`
+	Health           string         `json:"health"`
+	SourceOnline     bool           `json:"source_online"`
+	Stale            bool           `json:"stale"`
+	FoldersTotal     int            `json:"folders_total"`
+	FoldersByState   map[string]int `json:"folders_by_state"`
+	RemotesConnected int            `json:"remotes_connected"`
+	RemotesTotal     int            `json:"remotes_total"`
+	NeedBytes        ByteCount      `json:"need_bytes"`
+	DownloadBPS      float64        `json:"download_bps"`
+	UploadBPS        float64        `json:"upload_bps"`
+}
+
+// Summarize counts folders by lowercased state and remotes by connection, and
+// totals the bytes still needed across folders.
+func Summarize(snapshot DashboardSnapshot) Summary {
+	summary := Summary{
+		GeneratedAt:    snapshot.GeneratedAt,
+		Health:         snapshot.Health,
+		SourceOnline:   snapshot.SourceOnline,
+		Stale:          snapshot.Stale,
+		FoldersTotal:   len(snapshot.Folders),
+		FoldersByState: make(map[string]int),
+		RemotesTotal:   len(snapshot.Remotes),
+		DownloadBPS:    snapshot.Device.DownloadBPS,
+		UploadBPS:      snapshot.Device.UploadBPS,
+	}
+	for _, folder := range snapshot.Folders {
+		summary.FoldersByState[strings.ToLower(folder.State)]++
+		summary.NeedBytes += folder.NeedBytes
+	}
+	for _, remote := range snapshot.Remotes {
+		if remote.Connected {
+			summary.RemotesConnected++
+		}
+	}
+	return summary
+}
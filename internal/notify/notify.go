@@ -0,0 +1,34 @@
+// Package notify wakes every waiter when a new snapshot is published, so
+// streaming endpoints share one fan-out instead of each polling on a timer.
+package notify
+
+import "sync"
+
+// Broadcaster hands out a channel that is closed on the next Publish. Any
+// number of goroutines may wait on the same channel. The zero value is ready
+// to use.
+type Broadcaster struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// Wait returns a channel closed by the next Publish. Callers take it before
+// reading the current snapshot so a publish in between is not missed.
+func (b *Broadcaster) Wait() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.ch == nil {
+		b.ch = make(chan struct{})
+	}
+	return b.ch
+}
+
+// Publish wakes everyone waiting on the current channel.
+func (b *Broadcaster) Publish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.ch != nil {
+		close(b.ch)
+		b.ch = nil
+	}
+}
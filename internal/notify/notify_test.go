@@ -0,0 +1,32 @@
+package notify
+
+import "testing"
+
+func TestBroadcasterWakesEveryWaiterOnce(t *testing.T) {
+	var b Broadcaster
+	first, second := b.Wait(), b.Wait()
+	if first != second {
+		t.Fatalf("expected waiters before a publish to share a channel")
+	}
+	select {
+	case <-first:
+		t.Fatalf("expected no wake-up before Publish")
+	default:
+	}
+
+	b.Publish()
+	for _, ch := range []<-chan struct{}{first, second} {
+		select {
+		case <-ch:
+		default:
+			t.Fatalf("expected Publish to wake every waiter")
+		}
+	}
+
+	next := b.Wait()
+	select {
+	case <-next:
+		t.Fatalf("expected a fresh channel after Publish")
+	default:
+	}
+}
@@ -2,43 +2,144 @@ package syncthing
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
+
+	"syncthing-dashboard/internal/version"
 )
 
 var allowedReadPaths = map[string]struct{}{
-	"/rest/system/status":      {},
-	"/rest/system/version":     {},
-	"/rest/system/connections": {},
-	"/rest/stats/device":       {},
-	"/rest/stats/folder":       {},
-	"/rest/config":             {},
-	"/rest/db/status":          {},
-	"/rest/db/completion":      {},
+	"/rest/system/status":           {},
+	"/rest/system/version":          {},
+	"/rest/system/connections":      {},
+	"/rest/system/error":            {},
+	"/rest/stats/device":            {},
+	"/rest/stats/folder":            {},
+	"/rest/config":                  {},
+	"/rest/db/status":               {},
+	"/rest/db/completion":           {},
+	"/rest/db/ignores":              {},
+	"/rest/cluster/pending/devices": {},
+	"/rest/cluster/pending/folders": {},
+	"/rest/events":                  {},
+}
+
+// APIError is returned when Syncthing answers with a non-2xx status.
+type APIError struct {
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("request %s failed with status %d: %s", e.Path, e.StatusCode, e.Body)
 }
 
 // Client is a strict read-only Syncthing API client.
 type Client struct {
-	baseURL string
-	apiKey  string
-	http    *http.Client
+	baseURL          string
+	apiKey           string
+	maxResponseBytes int64
+	allowedPaths     map[string]struct{}
+	http             *http.Client
 }
 
-func NewClient(baseURL, apiKey string, timeout time.Duration, insecureSkipVerify bool) *Client {
+// ClientOptions tunes connection reuse and response limits towards the
+// Syncthing GUI. Zero values fall back to defaults sized for a single-host poller.
+type ClientOptions struct {
+	// MaxIdleConns caps idle connections kept open, in total and per host.
+	MaxIdleConns int
+	// IdleConnTimeout is how long an idle connection is kept between polls.
+	IdleConnTimeout time.Duration
+	// MaxResponseBytes caps how much of a response body is decoded.
+	MaxResponseBytes int64
+	// TLSCertSHA256 pins the GUI certificate by the hex SHA-256 of its DER
+	// encoding; colons are ignored. When set, only that certificate is
+	// accepted and insecureSkipVerify has no effect.
+	TLSCertSHA256 string
+	// ClientCert is presented to the GUI, or a gateway in front of it, when
+	// the server asks for a client certificate.
+	ClientCert *tls.Certificate
+	// ExtraReadPaths are allowed on top of the built-in read-only paths, for
+	// builds that read endpoints this one does not. They are not checked for
+	// being read-only.
+	ExtraReadPaths []string
+}
+
+const (
+	defaultMaxIdleConns     = 4
+	defaultIdleConnTimeout  = 90 * time.Second
+	defaultMaxResponseBytes = 8 << 20
+)
+
+// NewClient returns a read-only client for the Syncthing GUI at baseURL. A path
+// in baseURL, e.g. https://host/syncthing behind a reverse proxy, prefixes
+// every /rest/ request.
+func NewClient(baseURL, apiKey string, timeout time.Duration, insecureSkipVerify bool, opts ClientOptions) *Client {
+	maxIdleConns := opts.MaxIdleConns
+	if maxIdleConns <= 0 {
+		maxIdleConns = defaultMaxIdleConns
+	}
+	idleConnTimeout := opts.IdleConnTimeout
+	if idleConnTimeout <= 0 {
+		idleConnTimeout = defaultIdleConnTimeout
+	}
+	maxResponseBytes := opts.MaxResponseBytes
+	if maxResponseBytes <= 0 {
+		maxResponseBytes = defaultMaxResponseBytes
+	}
+
 	transport := http.DefaultTransport.(*http.Transport).Clone()
-	if insecureSkipVerify {
+	// Every request goes to the same host, so the per-host limit is the one
+	// that matters; keeping connections idle between polls avoids a new TLS
+	// handshake on every poll against an HTTPS GUI.
+	transport.MaxIdleConns = maxIdleConns
+	transport.MaxIdleConnsPerHost = maxIdleConns
+	transport.IdleConnTimeout = idleConnTimeout
+	if pin := NormalizeFingerprint(opts.TLSCertSHA256); pin != "" {
+		// Chain verification is replaced by the pin check, which is what lets
+		// the self-signed GUI certificate through.
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify:    true,
+			VerifyPeerCertificate: verifyPinnedCert(pin),
+		}
+	} else if insecureSkipVerify {
 		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	}
+	if opts.ClientCert != nil {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{*opts.ClientCert}
+	}
+
+	allowedPaths := allowedReadPaths
+	if len(opts.ExtraReadPaths) > 0 {
+		allowedPaths = make(map[string]struct{}, len(allowedReadPaths)+len(opts.ExtraReadPaths))
+		for path := range allowedReadPaths {
+			allowedPaths[path] = struct{}{}
+		}
+		for _, path := range opts.ExtraReadPaths {
+			allowedPaths[path] = struct{}{}
+		}
+	}
 
 	return &Client{
-		baseURL: strings.TrimRight(baseURL, "/"),
-		apiKey:  apiKey,
+		baseURL:          strings.TrimRight(baseURL, "/"),
+		apiKey:           apiKey,
+		maxResponseBytes: maxResponseBytes,
+		allowedPaths:     allowedPaths,
 		http: &http.Client{
 			Timeout:   timeout,
 			Transport: transport,
@@ -46,6 +147,25 @@ func NewClient(baseURL, apiKey string, timeout time.Duration, insecureSkipVerify
 	}
 }
 
+// NormalizeFingerprint lowercases a hex fingerprint and strips colons and
+// spaces, so "AB:CD" and "abcd" compare equal.
+func NormalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(strings.TrimSpace(fingerprint)))
+}
+
+func verifyPinnedCert(pin string) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("no server certificate presented")
+		}
+		sum := sha256.Sum256(rawCerts[0])
+		if got := hex.EncodeToString(sum[:]); got != pin {
+			return fmt.Errorf("server certificate fingerprint %s does not match pinned fingerprint", got)
+		}
+		return nil
+	}
+}
+
 func (c *Client) GetSystemStatus(ctx context.Context) (SystemStatusResponse, error) {
 	var out SystemStatusResponse
 	if err := c.getJSON(ctx, "/rest/system/status", nil, &out); err != nil {
@@ -70,6 +190,30 @@ func (c *Client) GetSystemConnections(ctx context.Context) (SystemConnectionsRes
 	return out, nil
 }
 
+func (c *Client) GetSystemErrors(ctx context.Context) (SystemErrorsResponse, error) {
+	var out SystemErrorsResponse
+	if err := c.getJSON(ctx, "/rest/system/error", nil, &out); err != nil {
+		return SystemErrorsResponse{}, err
+	}
+	return out, nil
+}
+
+func (c *Client) GetPendingDevices(ctx context.Context) (map[string]PendingDevice, error) {
+	var out map[string]PendingDevice
+	if err := c.getJSON(ctx, "/rest/cluster/pending/devices", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *Client) GetPendingFolders(ctx context.Context) (map[string]PendingFolder, error) {
+	var out map[string]PendingFolder
+	if err := c.getJSON(ctx, "/rest/cluster/pending/folders", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *Client) GetDeviceStats(ctx context.Context) (map[string]DeviceStats, error) {
 	var out map[string]DeviceStats
 	if err := c.getJSON(ctx, "/rest/stats/device", nil, &out); err != nil {
@@ -94,6 +238,21 @@ func (c *Client) GetConfig(ctx context.Context) (ConfigResponse, error) {
 	return out, nil
 }
 
+// GetScanProgressEvents returns FolderScanProgress events with an ID above
+// since, without waiting for new ones. Syncthing only emits them while
+// scanProgressIntervalS is positive, which is the default.
+func (c *Client) GetScanProgressEvents(ctx context.Context, since int64) ([]ScanProgressEvent, error) {
+	var out []ScanProgressEvent
+	query := url.Values{}
+	query.Set("events", "FolderScanProgress")
+	query.Set("since", strconv.FormatInt(since, 10))
+	query.Set("timeout", "0")
+	if err := c.getJSON(ctx, "/rest/events", query, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *Client) GetDBStatus(ctx context.Context, folderID string) (DBStatusResponse, error) {
 	var out DBStatusResponse
 	query := url.Values{}
@@ -114,8 +273,31 @@ func (c *Client) GetDBCompletion(ctx context.Context, folderID string) (DBComple
 	return out, nil
 }
 
+// GetDBIgnores returns the folder's ignore patterns and, when they failed to
+// load, the parse error. Only GET is used; it does not change the patterns.
+func (c *Client) GetDBIgnores(ctx context.Context, folderID string) (DBIgnoresResponse, error) {
+	var out DBIgnoresResponse
+	query := url.Values{}
+	query.Set("folder", folderID)
+	if err := c.getJSON(ctx, "/rest/db/ignores", query, &out); err != nil {
+		return DBIgnoresResponse{}, err
+	}
+	return out, nil
+}
+
+func (c *Client) GetDBDeviceCompletion(ctx context.Context, folderID, deviceID string) (DBCompletionResponse, error) {
+	var out DBCompletionResponse
+	query := url.Values{}
+	query.Set("folder", folderID)
+	query.Set("device", deviceID)
+	if err := c.getJSON(ctx, "/rest/db/completion", query, &out); err != nil {
+		return DBCompletionResponse{}, err
+	}
+	return out, nil
+}
+
 func (c *Client) getJSON(ctx context.Context, path string, query url.Values, out any) error {
-	if _, ok := allowedReadPaths[path]; !ok {
+	if _, ok := c.allowedPaths[path]; !ok {
 		return fmt.Errorf("path %q is not allowed in read-only mode", path)
 	}
 
@@ -129,6 +311,7 @@ func (c *Client) getJSON(ctx context.Context, path string, query url.Values, out
 		return fmt.Errorf("build request %s: %w", path, err)
 	}
 	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("User-Agent", "syncthing-dashboard/"+version.Version)
 
 	resp, err := c.http.Do(req)
 	if err != nil {
@@ -138,10 +321,16 @@ func (c *Client) getJSON(ctx context.Context, path string, query url.Values, out
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
-		return fmt.Errorf("request %s failed with status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(snippet)))
+		return &APIError{Path: path, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(snippet))}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+	// Read one byte past the cap so an oversized body is told apart from a
+	// truncated one.
+	body := &io.LimitedReader{R: resp.Body, N: c.maxResponseBytes + 1}
+	if err := json.NewDecoder(body).Decode(out); err != nil {
+		if body.N <= 0 {
+			return fmt.Errorf("response %s exceeds %d bytes", path, c.maxResponseBytes)
+		}
 		return fmt.Errorf("decode response %s: %w", path, err)
 	}
 
@@ -182,16 +371,54 @@ type ConnectionTotals struct {
 type ConnectionDetails struct {
 	Address       string `json:"address"`
 	Connected     bool   `json:"connected"`
+	Error         string `json:"error"`
 	InBytesTotal  int64  `json:"inBytesTotal"`
 	OutBytesTotal int64  `json:"outBytesTotal"`
 }
 
+type SystemErrorsResponse struct {
+	Errors []SystemError `json:"errors"`
+}
+
+type SystemError struct {
+	When    string `json:"when"`
+	Message string `json:"message"`
+}
+
+// PendingDevice is a device that tried to connect but is not configured,
+// keyed by device ID.
+type PendingDevice struct {
+	Time    string `json:"time"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// PendingFolder is a folder offered by one or more devices but not accepted,
+// keyed by folder ID.
+type PendingFolder struct {
+	OfferedBy map[string]PendingFolderOffer `json:"offeredBy"`
+}
+
+type PendingFolderOffer struct {
+	Time  string `json:"time"`
+	Label string `json:"label"`
+}
+
 type DeviceStats struct {
 	LastSeen string `json:"lastSeen"`
 }
 
 type FolderStats struct {
-	LastScan string `json:"lastScan"`
+	LastScan string         `json:"lastScan"`
+	LastFile FolderLastFile `json:"lastFile"`
+}
+
+// FolderLastFile is the most recent file Syncthing pulled into a folder. A
+// folder that never synced anything reports an empty filename and zero time.
+type FolderLastFile struct {
+	At       string `json:"at"`
+	Filename string `json:"filename"`
+	Deleted  bool   `json:"deleted"`
 }
 
 type ConfigResponse struct {
@@ -202,13 +429,22 @@ type ConfigResponse struct {
 type ConfigDevice struct {
 	DeviceID string `json:"deviceID"`
 	Name     string `json:"name"`
+	Paused   bool   `json:"paused"`
 }
 
 type ConfigFolder struct {
-	ID     string `json:"id"`
-	Label  string `json:"label"`
-	Path   string `json:"path"`
-	Paused bool   `json:"paused"`
+	ID      string         `json:"id"`
+	Label   string         `json:"label"`
+	Path    string         `json:"path"`
+	Type    string         `json:"type"`
+	Paused  bool           `json:"paused"`
+	Devices []FolderDevice `json:"devices"`
+
+	FSWatcherEnabled bool `json:"fsWatcherEnabled"`
+}
+
+type FolderDevice struct {
+	DeviceID string `json:"deviceID"`
 }
 
 type DBStatusResponse struct {
@@ -226,11 +462,43 @@ type DBStatusResponse struct {
 	ReceiveOnlyTotalItems   int64  `json:"receiveOnlyTotalItems"`
 	ReceiveOnlyChangedBytes int64  `json:"receiveOnlyChangedBytes"`
 	State                   string `json:"state"`
+	WatchError              string `json:"watchError"`
+	// Disk space is not reported by stock Syncthing; it is picked up when an
+	// upstream (fork or proxy) includes it.
+	DiskFreeBytes  *int64 `json:"diskFreeBytes"`
+	DiskTotalBytes *int64 `json:"diskTotalBytes"`
+}
+
+type DBIgnoresResponse struct {
+	// Ignore holds the .stignore lines as written, including blank lines and
+	// comments.
+	Ignore   []string `json:"ignore"`
+	Expanded []string `json:"expanded"`
+	// Error is set when the patterns failed to parse.
+	Error string `json:"error"`
+}
+
+type ScanProgressEvent struct {
+	ID   int64 `json:"id"`
+	Data struct {
+		Folder  string `json:"folder"`
+		Current int64  `json:"current"`
+		Total   int64  `json:"total"`
+	} `json:"data"`
 }
 
 type DBCompletionResponse struct {
-	Completion  float64 `json:"completion"`
-	NeedBytes   int64   `json:"needBytes"`
-	NeedItems   int64   `json:"needItems"`
-	GlobalBytes int64   `json:"globalBytes"`
+	// Completion is nil when the field is missing, so an absent value is not
+	// mistaken for 0%.
+	Completion  *float64 `json:"completion"`
+	NeedBytes   int64    `json:"needBytes"`
+	NeedItems   int64    `json:"needItems"`
+	GlobalBytes int64    `json:"globalBytes"`
+	// RemoteState is set for per-device completion: "valid", "paused",
+	// "notSharing" or "unknown". Older Syncthing versions leave it empty.
+	RemoteState string `json:"remoteState"`
 }
+
+// RemoteStateNotSharing is the DBCompletionResponse.RemoteState of a device
+// the folder is shared with that has not shared it back.
+const RemoteStateNotSharing = "notSharing"
@@ -2,11 +2,25 @@ package syncthing
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"syncthing-dashboard/internal/version"
 )
 
 func TestGetJSONRejectsUnknownPath(t *testing.T) {
@@ -16,7 +30,7 @@ func TestGetJSONRejectsUnknownPath(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	client := NewClient(ts.URL, "token", 2*time.Second, false)
+	client := NewClient(ts.URL, "token", 2*time.Second, false, ClientOptions{})
 
 	var out map[string]any
 	err := client.getJSON(context.Background(), "/rest/system/restart", nil, &out)
@@ -28,6 +42,33 @@ func TestGetJSONRejectsUnknownPath(t *testing.T) {
 	}
 }
 
+func TestGetJSONAllowsExtraReadPaths(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, "token", 2*time.Second, false, ClientOptions{ExtraReadPaths: []string{"/rest/svc/report"}})
+
+	var out map[string]any
+	if err := client.getJSON(context.Background(), "/rest/svc/report", nil, &out); err != nil {
+		t.Fatalf("expected extra path to be allowed, got %v", err)
+	}
+	if err := client.getJSON(context.Background(), "/rest/system/status", nil, &out); err != nil {
+		t.Fatalf("expected built-in paths to stay allowed, got %v", err)
+	}
+	err := client.getJSON(context.Background(), "/rest/system/restart", nil, &out)
+	if err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Fatalf("expected unlisted path to be rejected, got %v", err)
+	}
+
+	// Extra paths belong to the client they were given to.
+	plain := NewClient(ts.URL, "token", 2*time.Second, false, ClientOptions{})
+	if err := plain.getJSON(context.Background(), "/rest/svc/report", nil, &out); err == nil {
+		t.Fatalf("expected extra path to stay blocked for other clients")
+	}
+}
+
 func TestGetDBStatusUsesAllowlistedPath(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/rest/db/status" {
@@ -46,7 +87,7 @@ func TestGetDBStatusUsesAllowlistedPath(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	client := NewClient(ts.URL, "secret", 2*time.Second, false)
+	client := NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{})
 	status, err := client.GetDBStatus(context.Background(), "docs")
 	if err != nil {
 		t.Fatalf("GetDBStatus failed: %v", err)
@@ -56,6 +97,25 @@ func TestGetDBStatusUsesAllowlistedPath(t *testing.T) {
 	}
 }
 
+func TestClientKeepsBaseURLPathPrefix(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/syncthing/rest/system/status" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"myID":"LOCAL-1"}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL+"/syncthing/", "secret", 2*time.Second, false, ClientOptions{})
+	status, err := client.GetSystemStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetSystemStatus failed: %v", err)
+	}
+	if status.MyID != "LOCAL-1" {
+		t.Fatalf("unexpected myID: %q", status.MyID)
+	}
+}
+
 func TestGetDBCompletionUsesAllowlistedPath(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/rest/db/completion" {
@@ -74,12 +134,261 @@ func TestGetDBCompletionUsesAllowlistedPath(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	client := NewClient(ts.URL, "secret", 2*time.Second, false)
+	client := NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{})
 	status, err := client.GetDBCompletion(context.Background(), "docs")
 	if err != nil {
 		t.Fatalf("GetDBCompletion failed: %v", err)
 	}
-	if status.Completion != 25.5 || status.NeedItems != 5 {
+	if status.Completion == nil || *status.Completion != 25.5 || status.NeedItems != 5 {
 		t.Fatalf("unexpected completion payload: %+v", status)
 	}
 }
+
+func TestGetFolderStatsParsesLastFile(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/stats/folder" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{
+			"docs":{"lastScan":"2026-02-05T20:10:00Z","lastFile":{"at":"2026-02-05T20:07:00Z","filename":"reports/report.pdf","deleted":false}},
+			"empty":{"lastScan":"2026-02-05T20:10:00Z","lastFile":{"at":"0001-01-01T00:00:00Z","filename":""}},
+			"old":{"lastScan":"2026-02-05T20:10:00Z"}
+		}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{})
+	stats, err := client.GetFolderStats(context.Background())
+	if err != nil {
+		t.Fatalf("GetFolderStats failed: %v", err)
+	}
+	if got := stats["docs"].LastFile; got.Filename != "reports/report.pdf" || got.At != "2026-02-05T20:07:00Z" || got.Deleted {
+		t.Fatalf("unexpected lastFile: %+v", got)
+	}
+	if stats["empty"].LastFile.Filename != "" || stats["old"].LastFile.Filename != "" {
+		t.Fatalf("expected empty lastFile for folders without one: %+v", stats)
+	}
+}
+
+func TestGetSystemErrorsParsesErrorList(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/system/error" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Method != http.MethodGet {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		_, _ = w.Write([]byte(`{"errors":[{"when":"2026-02-05T19:00:00.123456789Z","message":"Failed to create folder marker"},{"when":"2026-02-05T19:05:00Z","message":"Out of disk space"}]}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{})
+	out, err := client.GetSystemErrors(context.Background())
+	if err != nil {
+		t.Fatalf("GetSystemErrors failed: %v", err)
+	}
+	if len(out.Errors) != 2 {
+		t.Fatalf("expected 2 errors, got %d", len(out.Errors))
+	}
+	if out.Errors[0].When != "2026-02-05T19:00:00.123456789Z" || out.Errors[1].Message != "Out of disk space" {
+		t.Fatalf("unexpected errors payload: %+v", out.Errors)
+	}
+}
+
+func TestGetPendingDevicesParsesPayload(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/cluster/pending/devices" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"P56IOI7-MZJNU2Y-IQGDREY-DM2MGTI-MGL3BXN-PQ6W5BM-TBBZ4TJ-XZWICQ2":{"time":"2026-02-05T19:00:00Z","name":"new-laptop","address":"192.168.1.20:22000"}}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{})
+	out, err := client.GetPendingDevices(context.Background())
+	if err != nil {
+		t.Fatalf("GetPendingDevices failed: %v", err)
+	}
+	pending, ok := out["P56IOI7-MZJNU2Y-IQGDREY-DM2MGTI-MGL3BXN-PQ6W5BM-TBBZ4TJ-XZWICQ2"]
+	if !ok || pending.Name != "new-laptop" || pending.Address != "192.168.1.20:22000" || pending.Time != "2026-02-05T19:00:00Z" {
+		t.Fatalf("unexpected pending devices payload: %+v", out)
+	}
+}
+
+func TestGetPendingFoldersParsesPayload(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/cluster/pending/folders" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"cpkn4-57ysy":{"offeredBy":{"P56IOI7":{"time":"2026-02-05T19:00:00Z","label":"Photos","receiveEncrypted":false,"remoteEncrypted":false},"DESK":{"time":"2026-02-05T19:01:00Z","label":"Photos"}}}}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{})
+	out, err := client.GetPendingFolders(context.Background())
+	if err != nil {
+		t.Fatalf("GetPendingFolders failed: %v", err)
+	}
+	offers := out["cpkn4-57ysy"].OfferedBy
+	if len(offers) != 2 || offers["P56IOI7"].Label != "Photos" || offers["DESK"].Time != "2026-02-05T19:01:00Z" {
+		t.Fatalf("unexpected pending folders payload: %+v", out)
+	}
+}
+
+func TestGetJSONSendsUserAgent(t *testing.T) {
+	var userAgent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		_, _ = w.Write([]byte(`{"version":"v2.0.1"}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{})
+	if _, err := client.GetSystemVersion(context.Background()); err != nil {
+		t.Fatalf("GetSystemVersion failed: %v", err)
+	}
+	if userAgent != "syncthing-dashboard/"+version.Version {
+		t.Fatalf("unexpected User-Agent: %q", userAgent)
+	}
+}
+
+func TestClientReusesConnectionBetweenRequests(t *testing.T) {
+	var newConns atomic.Int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"v2.0.1"}`))
+	}))
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConns.Add(1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	client := NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{})
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetSystemVersion(context.Background()); err != nil {
+			t.Fatalf("GetSystemVersion failed: %v", err)
+		}
+	}
+	if got := newConns.Load(); got != 1 {
+		t.Fatalf("expected sequential requests to share one connection, got %d", got)
+	}
+}
+
+func TestGetJSONReturnsAPIErrorWithStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such folder", http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{})
+	_, err := client.GetDBStatus(context.Background(), "gone")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Path != "/rest/db/status" || apiErr.Body != "no such folder" {
+		t.Fatalf("unexpected APIError: %+v", apiErr)
+	}
+	if !strings.Contains(err.Error(), "failed with status 404") {
+		t.Fatalf("expected the status in the message, got %v", err)
+	}
+}
+
+func TestGetJSONRejectsOversizedResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"` + strings.Repeat("x", 4096) + `"}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{MaxResponseBytes: 1024})
+	_, err := client.GetSystemVersion(context.Background())
+	if err == nil {
+		t.Fatalf("expected error for response over the limit")
+	}
+	if !strings.Contains(err.Error(), "exceeds 1024 bytes") {
+		t.Fatalf("expected size limit error, got %v", err)
+	}
+
+	client = NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{MaxResponseBytes: 8192})
+	if _, err := client.GetSystemVersion(context.Background()); err != nil {
+		t.Fatalf("expected response under the limit to decode, got %v", err)
+	}
+}
+
+func TestClientVerifiesPinnedCertificate(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"v2.0.1"}`))
+	}))
+	defer ts.Close()
+
+	sum := sha256.Sum256(ts.Certificate().Raw)
+	fingerprint := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	client := NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{TLSCertSHA256: fingerprint})
+	if _, err := client.GetSystemVersion(context.Background()); err != nil {
+		t.Fatalf("expected pinned self-signed certificate to be accepted, got %v", err)
+	}
+
+	wrong := strings.Repeat("00", sha256.Size)
+	client = NewClient(ts.URL, "secret", 2*time.Second, true, ClientOptions{TLSCertSHA256: wrong})
+	_, err := client.GetSystemVersion(context.Background())
+	if err == nil {
+		t.Fatalf("expected mismatched fingerprint to be rejected even with insecure skip verify")
+	}
+	if !strings.Contains(err.Error(), "does not match pinned fingerprint") {
+		t.Fatalf("expected pin mismatch error, got %v", err)
+	}
+}
+
+func TestClientPresentsClientCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "dashboard"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCert := &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"v2.0.1"}`))
+	}))
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	ts.StartTLS()
+	defer ts.Close()
+
+	client := NewClient(ts.URL, "secret", 2*time.Second, true, ClientOptions{})
+	if _, err := client.GetSystemVersion(context.Background()); err == nil {
+		t.Fatalf("expected request without client certificate to be rejected")
+	}
+
+	client = NewClient(ts.URL, "secret", 2*time.Second, true, ClientOptions{ClientCert: clientCert})
+	if _, err := client.GetSystemVersion(context.Background()); err != nil {
+		t.Fatalf("expected client certificate to be accepted with insecure skip verify, got %v", err)
+	}
+
+	sum := sha256.Sum256(ts.Certificate().Raw)
+	client = NewClient(ts.URL, "secret", 2*time.Second, false, ClientOptions{TLSCertSHA256: hex.EncodeToString(sum[:]), ClientCert: clientCert})
+	if _, err := client.GetSystemVersion(context.Background()); err != nil {
+		t.Fatalf("expected client certificate to be accepted with a pinned server, got %v", err)
+	}
+}
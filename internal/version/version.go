@@ -0,0 +1,6 @@
+// Package version holds the build version of the dashboard.
+package version
+
+// Version is set at build time, e.g.
+// go build -ldflags "-X syncthing-dashboard/internal/version.Version=v1.2.3".
+var Version = "dev"
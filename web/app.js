@@ -4,6 +4,7 @@ const pageTitle = document.getElementById("page-title");
 const pageSubtitle = document.getElementById("page-subtitle");
 const generatedAt = document.getElementById("generated-at");
 const globalStatus = document.getElementById("global-status");
+const demoBadge = document.getElementById("demo-badge");
 const alertsSection = document.getElementById("alerts-section");
 const alertsList = document.getElementById("alerts-list");
 const foldersCount = document.getElementById("folders-count");
@@ -14,6 +15,7 @@ const remotesCount = document.getElementById("remotes-count");
 const remotesSummary = document.getElementById("remotes-summary");
 const remotesList = document.getElementById("remotes-list");
 let refreshMs = DEFAULT_REFRESH_MS;
+let timeZone = undefined;
 let refreshTimer = null;
 
 const BYTES_PER_GIB = 1024 ** 3;
@@ -46,7 +48,11 @@ function formatDate(value) {
   if (Number.isNaN(date.getTime())) {
     return value;
   }
-  return date.toLocaleString();
+  try {
+    return date.toLocaleString(undefined, { timeZone });
+  } catch {
+    return date.toLocaleString();
+  }
 }
 
 function formatUptime(seconds) {
@@ -93,11 +99,18 @@ function deviceIconSVG() {
   return `<svg viewBox="0 0 16 16" aria-hidden="true" focusable="false"><path fill="currentColor" d="M2 3.5A1.5 1.5 0 0 1 3.5 2h9A1.5 1.5 0 0 1 14 3.5v6a1.5 1.5 0 0 1-1.5 1.5H9.6l.9 1.5H12a.5.5 0 0 1 0 1H4a.5.5 0 0 1 0-1h1.5l.9-1.5H3.5A1.5 1.5 0 0 1 2 9.5v-6zm1.5-.5a.5.5 0 0 0-.5.5v6a.5.5 0 0 0 .5.5h9a.5.5 0 0 0 .5-.5v-6a.5.5 0 0 0-.5-.5h-9z"/></svg>`;
 }
 
+function isInitializing(data) {
+  return (data.alerts || []).some((alert) => alert.code === "INITIALIZING");
+}
+
 function statusClassForGlobal(data) {
+  if (isInitializing(data)) {
+    return "status-warn";
+  }
   if (!data.source_online) {
     return "status-critical";
   }
-  if (data.stale) {
+  if (data.stale || data.health === "degraded") {
     return "status-warn";
   }
   return "status-ok";
@@ -124,8 +137,12 @@ function folderStatus(folder) {
   if (state === "error") {
     return { label: "Error", cls: "folder-state-error", phase: "error", rightText: "Error" };
   }
+  if (state === "pending") {
+    return { label: "Pending", cls: "folder-state-paused", phase: "pending", rightText: "Pending" };
+  }
 
-  if (localChangesItems > 0 && needItems === 0 && needBytes === 0) {
+  const receiveOnly = !folder.type || folder.type === "receiveonly";
+  if (receiveOnly && localChangesItems > 0 && needItems === 0 && needBytes === 0) {
     return {
       label: "Local Additions",
       cls: "folder-state-local",
@@ -135,6 +152,18 @@ function folderStatus(folder) {
     };
   }
 
+  const scanPct = Number(folder.scan_progress_pct);
+  if (state === "scanning" && folder.scan_progress_pct != null && Number.isFinite(scanPct)) {
+    const percent = Math.max(0, Math.min(100, Math.floor(scanPct)));
+    return {
+      label: "Scanning",
+      cls: "folder-state-sync",
+      phase: "syncing",
+      rightText: `Scanning ${percent}%`,
+      progressWidth: percent,
+    };
+  }
+
   if (needItems > 0 || needBytes > 0 || state.includes("sync") || state.includes("scan") || state.includes("wait")) {
     const percentText = progress.percent === null ? "" : ` (${progress.percent}%)`;
     const sizeText = progress.remaining > 0 ? `, ${formatGiB(progress.remaining)}` : "";
@@ -160,19 +189,28 @@ function renderAlerts(data) {
 
   alertsSection.hidden = false;
   alertsList.innerHTML = alerts.map((alert) => {
-    const cls = alert.severity === "critical" ? "alert-critical" : "alert-warn";
+    const cls = alert.severity === "critical"
+      ? "alert-critical"
+      : alert.severity === "info"
+        ? "alert-info"
+        : "alert-warn";
     return `<div class="alert-item ${cls}">${escapeHTML(alert.message)}</div>`;
   }).join("");
 }
 
 function renderDevice(data) {
+  demoBadge.hidden = !data.demo;
   const globalClass = statusClassForGlobal(data);
   globalStatus.className = `status-pill ${globalClass}`;
-  globalStatus.textContent = !data.source_online
-    ? "Source Offline"
-    : data.stale
-      ? "Stale"
-      : "Healthy";
+  globalStatus.textContent = isInitializing(data)
+    ? "Starting"
+    : !data.source_online
+      ? "Source Offline"
+      : data.stale
+        ? "Stale"
+        : data.health === "degraded"
+          ? "Degraded"
+          : "Healthy";
 
   const device = data.device || {};
   const rows = [
@@ -229,7 +267,7 @@ function renderFolders(data) {
               <span class="entity-icon folder-icon">${folderIconSVG()}</span>
               <div class="entity-text">
                 <p class="folder-name">${escapeHTML(folder.label || folder.id || "Unnamed Folder")}</p>
-                <p class="folder-path">${escapeHTML(folder.path || "-")}</p>
+                <p class="folder-path">${escapeHTML([folder.group, folder.path || "-"].filter(Boolean).join(" • "))}</p>
               </div>
             </div>
           </div>
@@ -305,6 +343,7 @@ function render(data) {
   if (Number.isFinite(serverPoll) && serverPoll > 0 && serverPoll !== refreshMs) {
     refreshMs = serverPoll;
   }
+  timeZone = data.timezone || undefined;
   generatedAt.textContent = formatDate(data.generated_at);
   renderAlerts(data);
   renderDevice(data);
@@ -2,5 +2,5 @@ package web
 
 import "embed"
 
-//go:embed index.html styles.css app.js
+//go:embed index.html styles.css app.js favicon.svg
 var Files embed.FS